@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BulkUpdateOrders takes the order ids in the body, or selects the orders with
+// the same query parameters as the order search when no ids are given.
+func (ctrl *ControllerImpl) BulkUpdateOrders(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), time.Minute)
+	defer cancel()
+
+	var reqBody web.BulkOrderRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid bulk order data")
+	}
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	if len(reqBody.Ids) == 0 && !filter.Narrows() {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Either ids or a filter is required")
+	}
+
+	username, _ := c.Locals("username").(string)
+	report, err := ctrl.svc.BulkUpdateOrders(ctx, &reqBody, filter, username)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.BulkOrderReport](c, fiber.StatusOK, "Bulk update processed", report)
+}
@@ -18,4 +18,78 @@ type Controller interface {
 	GetLog(c *fiber.Ctx) error
 	AddOrders(c *fiber.Ctx) error
 	DeleteUserById(c *fiber.Ctx) error
+	GetDailyCapacities(c *fiber.Ctx) error
+	SaveDailyCapacity(c *fiber.Ctx) error
+	DeleteDailyCapacity(c *fiber.Ctx) error
+	GetProductionSettings(c *fiber.Ctx) error
+	UpdateProductionSettings(c *fiber.Ctx) error
+	GetBlackoutDates(c *fiber.Ctx) error
+	AddBlackoutDate(c *fiber.Ctx) error
+	DeleteBlackoutDate(c *fiber.Ctx) error
+	GetProductionSheet(c *fiber.Ctx) error
+	GetDeliveryManifest(c *fiber.Ctx) error
+	GetDeliveryZones(c *fiber.Ctx) error
+	AddDeliveryZone(c *fiber.Ctx) error
+	UpdateDeliveryZone(c *fiber.Ctx) error
+	DeleteDeliveryZone(c *fiber.Ctx) error
+	GetRegions(c *fiber.Ctx) error
+	GetRegion(c *fiber.Ctx) error
+	SearchRegions(c *fiber.Ctx) error
+	AddPayment(c *fiber.Ctx) error
+	GetPayments(c *fiber.Ctx) error
+	VoidPayment(c *fiber.Ctx) error
+	UploadPaymentProof(c *fiber.Ctx) error
+	GetPaymentProofs(c *fiber.Ctx) error
+	GetPaymentProofFile(c *fiber.Ctx) error
+	ApprovePaymentProof(c *fiber.Ctx) error
+	RejectPaymentProof(c *fiber.Ctx) error
+	CreatePaymentLink(c *fiber.Ctx) error
+	PaymentNotification(c *fiber.Ctx) error
+	GetPaymentLinks(c *fiber.Ctx) error
+	ResolvePaymentLink(c *fiber.Ctx) error
+	GetInvoice(c *fiber.Ctx) error
+	AddQuotation(c *fiber.Ctx) error
+	GetQuotations(c *fiber.Ctx) error
+	GetQuotation(c *fiber.Ctx) error
+	ReviseQuotation(c *fiber.Ctx) error
+	UpdateQuotationStatus(c *fiber.Ctx) error
+	ConvertQuotation(c *fiber.Ctx) error
+	GetQuotationPDF(c *fiber.Ctx) error
+	GetVouchers(c *fiber.Ctx) error
+	AddVoucher(c *fiber.Ctx) error
+	UpdateVoucher(c *fiber.Ctx) error
+	DeactivateVoucher(c *fiber.Ctx) error
+	GetDiscountRules(c *fiber.Ctx) error
+	AddDiscountRule(c *fiber.Ctx) error
+	UpdateDiscountRule(c *fiber.Ctx) error
+	DeleteDiscountRule(c *fiber.Ctx) error
+	AddSubscription(c *fiber.Ctx) error
+	GetSubscriptions(c *fiber.Ctx) error
+	GetSubscription(c *fiber.Ctx) error
+	PauseSubscription(c *fiber.Ctx) error
+	ResumeSubscription(c *fiber.Ctx) error
+	CancelSubscription(c *fiber.Ctx) error
+	SkipSubscriptionDate(c *fiber.Ctx) error
+	UnskipSubscriptionDate(c *fiber.Ctx) error
+	GetReservationPolicies(c *fiber.Ctx) error
+	SaveReservationPolicy(c *fiber.Ctx) error
+	EditOrder(c *fiber.Ctx) error
+	GetOrderHistory(c *fiber.Ctx) error
+	ExportOrders(c *fiber.Ctx) error
+	BulkUpdateOrders(c *fiber.Ctx) error
+	GetOrderNotes(c *fiber.Ctx) error
+	AddOrderNote(c *fiber.Ctx) error
+	TrackOrder(c *fiber.Ctx) error
+	GetTrackingLink(c *fiber.Ctx) error
+	GetTrackingQRCode(c *fiber.Ctx) error
+	RequestRefund(c *fiber.Ctx) error
+	GetRefunds(c *fiber.Ctx) error
+	GetOrderRefunds(c *fiber.Ctx) error
+	ApproveRefund(c *fiber.Ctx) error
+	RejectRefund(c *fiber.Ctx) error
+	GetCreditNotes(c *fiber.Ctx) error
+	ApplyCreditNote(c *fiber.Ctx) error
+	SubmitFeedback(c *fiber.Ctx) error
+	GetFeedback(c *fiber.Ctx) error
+	ModerateFeedback(c *fiber.Ctx) error
 }
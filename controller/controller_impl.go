@@ -2,6 +2,10 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
 	"khaira-admin/domain"
 	"khaira-admin/helper"
 	"khaira-admin/service"
@@ -43,6 +47,7 @@ func (ctrl *ControllerImpl) AddProduct(c *fiber.Ctx) error {
 	reqBody.Id = c.FormValue("id")
 	reqBody.Name = c.FormValue("name")
 	reqBody.Description = c.FormValue("description")
+	reqBody.Category = c.FormValue("category")
 
 	price, err := strconv.Atoi(c.FormValue("price"))
 	if err != nil {
@@ -73,6 +78,9 @@ func (ctrl *ControllerImpl) AddProduct(c *fiber.Ctx) error {
 	return web.SuccessResponse[*domain.Domain](c, fiber.StatusCreated, "Product added successfully", result)
 }
 
+// GetProducts lists the products with their approved ratings. Each item is a
+// product object with average_rating and rating_count added alongside its
+// own fields, so clients that read the product fields are not affected.
 func (ctrl *ControllerImpl) GetProducts(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
 	defer cancel()
@@ -81,7 +89,7 @@ func (ctrl *ControllerImpl) GetProducts(c *fiber.Ctx) error {
 	if err != nil {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load products")
 	}
-	return web.SuccessResponse[[]*domain.Domain](c, fiber.StatusOK, "Products loaded successfully", products)
+	return web.SuccessResponse[[]*domain.RatedProduct](c, fiber.StatusOK, "Products loaded successfully", products)
 }
 
 func (ctrl *ControllerImpl) DeleteProduct(c *fiber.Ctx) error {
@@ -103,6 +111,7 @@ func (ctrl *ControllerImpl) UpdateProduct(c *fiber.Ctx) error {
 	id := c.Params("id")
 	name := c.FormValue("name")
 	description := c.FormValue("description")
+	category := c.FormValue("category")
 	stockStr := c.FormValue("stock")
 	priceStr := c.FormValue("price")
 
@@ -120,6 +129,7 @@ func (ctrl *ControllerImpl) UpdateProduct(c *fiber.Ctx) error {
 		Description: description,
 		Stock:       stock,
 		Price:       price,
+		Category:    category,
 	}
 
 	response, err := ctrl.svc.UpdateProduct(ctx, reqBody, id)
@@ -129,15 +139,46 @@ func (ctrl *ControllerImpl) UpdateProduct(c *fiber.Ctx) error {
 	return web.SuccessResponse(c, fiber.StatusOK, "Product updated successfully", response)
 }
 
+// GetOrders returns one page of orders: 50 by default, up to 200 with
+// ?limit=, and the next page through the X-Next-Cursor header. ?limit=all
+// turns paging off and returns every matching order, for clients that still
+// expect the whole list.
 func (ctrl *ControllerImpl) GetOrders(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
 	defer cancel()
 
-	orders, err := ctrl.svc.GetOrders(ctx)
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	if c.Query("limit") == limitAll {
+		if filter.SortBy == "" {
+			filter.SortBy = domain.OrderSortCreatedAt
+			filter.Descending = true
+		}
+		orders := []*domain.Orders{}
+		err = ctrl.svc.ExportOrders(ctx, filter, func(order *domain.Orders) error {
+			orders = append(orders, order)
+			return nil
+		})
+		if err != nil {
+			return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load orders")
+		}
+		c.Set("X-Total-Count", strconv.Itoa(len(orders)))
+		return web.SuccessResponse[[]*domain.Orders](c, fiber.StatusOK, "Orders loaded successfully", orders)
+	}
+	page, err := ctrl.svc.SearchOrders(ctx, filter, c.Query("cursor"))
+	if errors.Is(err, service.ErrInvalidCursor) {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
 	if err != nil {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load orders")
 	}
-	return web.SuccessResponse[[]*domain.Orders](c, fiber.StatusOK, "Orders loaded successfully", orders)
+	c.Set("X-Total-Count", strconv.Itoa(page.Total))
+	if page.NextCursor != "" {
+		c.Set("X-Next-Cursor", page.NextCursor)
+	}
+	return web.SuccessResponse[[]*domain.Orders](c, fiber.StatusOK, "Orders loaded successfully", page.Orders)
 }
 
 func (ctrl *ControllerImpl) UpdateOrder(c *fiber.Ctx) error {
@@ -150,7 +191,8 @@ func (ctrl *ControllerImpl) UpdateOrder(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	if err := ctrl.svc.UpdateOrder(ctx, &reqBody, id); err != nil {
+	username, _ := c.Locals("username").(string)
+	if err := ctrl.svc.UpdateOrder(ctx, &reqBody, id, username); err != nil {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to update order")
 	}
 	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Order updated successfully", nil)
@@ -162,6 +204,10 @@ func (ctrl *ControllerImpl) DeleteOrder(c *fiber.Ctx) error {
 
 	id := c.Params("id")
 	if err := ctrl.svc.DeleteOrder(ctx, id); err != nil {
+		var invalid *service.ValidationError
+		if errors.As(err, &invalid) {
+			return web.ErrorResponse(c, fiber.StatusConflict, "Conflict", invalid.Message)
+		}
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to delete order")
 	}
 	return web.SuccessResponse[interface{}](c, fiber.StatusNoContent, "Order deleted successfully", nil)
@@ -194,8 +240,12 @@ func (ctrl *ControllerImpl) GetOrdersByUsername(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
 	defer cancel()
 
+	expand, err := parseOrderExpand(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
 	username := c.Params("username")
-	result, err := ctrl.svc.GetOrdersByUsername(ctx, username)
+	result, err := ctrl.svc.GetOrdersByUsername(ctx, username, expand)
 	if err != nil {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Orders not found")
 	}
@@ -206,8 +256,12 @@ func (ctrl *ControllerImpl) GetOrderById(c *fiber.Ctx) error {
 	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
 	defer cancel()
 
+	expand, err := parseOrderExpand(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
 	id := c.Params("id")
-	result, err := ctrl.svc.GetOrderById(ctx, id)
+	result, err := ctrl.svc.GetOrderById(ctx, id, expand)
 	if err != nil {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Order not found")
 	}
@@ -233,6 +287,10 @@ func (ctrl *ControllerImpl) AddOrders(c *fiber.Ctx) error {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "error", err.Error())
 	}
 
+	if key := c.Get("Idempotency-Key"); key != "" {
+		return ctrl.addOrdersIdempotent(c, key, &order)
+	}
+
 	err = ctrl.svc.AddOrders(c.Context(), &order)
 	if err != nil {
 		return web.ErrorResponse(c, fiber.StatusBadRequest, "error", err.Error())
@@ -241,6 +299,35 @@ func (ctrl *ControllerImpl) AddOrders(c *fiber.Ctx) error {
 	return web.SuccessResponse[any](c, fiber.StatusNoContent, "ok", "Success")
 }
 
+// addOrdersIdempotent handles an order retried under the same Idempotency-Key
+// by replaying the first response instead of creating the order again.
+func (ctrl *ControllerImpl) addOrdersIdempotent(c *fiber.Ctx, key string, order *domain.Orders) error {
+	if len(key) > 255 {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "error", "Idempotency-Key is too long")
+	}
+	sum := sha256.Sum256(append([]byte(c.Method()+" "+c.Path()+"\n"), c.Body()...))
+	request := &domain.IdempotencyKey{
+		Key:            key,
+		Fingerprint:    hex.EncodeToString(sum[:]),
+		ResponseStatus: fiber.StatusNoContent,
+	}
+
+	record, err := ctrl.svc.AddOrdersIdempotent(c.Context(), request, order)
+	switch {
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		return web.ErrorResponse(c, fiber.StatusUnprocessableEntity, "error", err.Error())
+	case errors.Is(err, sql.ErrNoRows):
+		return web.ErrorResponse(c, fiber.StatusConflict, "error", "A request with this Idempotency-Key is still being processed")
+	case err != nil:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "error", err.Error())
+	}
+
+	if record.Replayed {
+		c.Set("Idempotent-Replayed", "true")
+	}
+	return web.SuccessResponse[any](c, record.ResponseStatus, "ok", "Success")
+}
+
 func (ctrl *ControllerImpl) DeleteUserById(c *fiber.Ctx) error {
 	userId := c.Params("id")
 	if err := ctrl.svc.DeleteUserById(c.Context(), userId); err != nil {
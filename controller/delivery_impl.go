@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetDeliveryZones(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetDeliveryZones(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load delivery zones")
+	}
+	return web.SuccessResponse[[]*domain.DeliveryZone](c, fiber.StatusOK, "Delivery zones loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) AddDeliveryZone(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	reqBody := domain.DeliveryZone{Deliverable: true}
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid delivery zone data")
+	}
+	if err := ctrl.svc.AddDeliveryZone(ctx, &reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to add delivery zone")
+	}
+	return web.SuccessResponse[*domain.DeliveryZone](c, fiber.StatusCreated, "Delivery zone added successfully", &reqBody)
+}
+
+func (ctrl *ControllerImpl) UpdateDeliveryZone(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	reqBody := domain.DeliveryZone{Deliverable: true}
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid delivery zone data")
+	}
+	id := c.Params("id")
+	if err := ctrl.svc.UpdateDeliveryZone(ctx, &reqBody, id); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to update delivery zone")
+	}
+	return web.SuccessResponse[*domain.DeliveryZone](c, fiber.StatusOK, "Delivery zone updated successfully", &reqBody)
+}
+
+func (ctrl *ControllerImpl) DeleteDeliveryZone(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	id := c.Params("id")
+	if err := ctrl.svc.DeleteDeliveryZone(ctx, id); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to delete delivery zone")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusNoContent, "Delivery zone deleted successfully", nil)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) EditOrder(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.EditOrderRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid order data")
+	}
+
+	username, _ := c.Locals("username").(string)
+	order, err := ctrl.svc.EditOrder(ctx, &reqBody, c.Params("id"), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.Orders](c, fiber.StatusOK, "Order updated successfully", order)
+}
+
+func (ctrl *ControllerImpl) GetOrderHistory(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	history, err := ctrl.svc.GetOrderHistory(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to load order history")
+	}
+	return web.SuccessResponse[[]*domain.OrderHistory](c, fiber.StatusOK, "Order history loaded successfully", history)
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var orderExportHeader = []string{"No. Invoice", "Kode Pesanan", "ID Pesanan", "Tanggal Pesan", "Tanggal Kirim", "Slot", "Kurir", "Pemesan", "Username", "Telepon", "Alamat",
+	"Kecamatan", "Desa", "Produk", "Varian", "Jumlah", "Subtotal", "Diskon", "Ongkir", "Total", "Dibayar", "Status Bayar", "Status"}
+
+func orderExportRow(order *domain.Orders) []any {
+	var createdAt, deliveryDate any
+	if order.CreatedAt != nil {
+		createdAt = helper.Tanggal(order.CreatedAt.In(helper.Location()))
+	}
+	if order.DeliveryDate != nil {
+		deliveryDate = helper.Tanggal(order.DeliveryDate.Time)
+	}
+	return []any{order.InvoiceNumber, order.Code, order.Id, createdAt, deliveryDate, order.DeliverySlot, order.Courier, order.Name, order.Username, order.Phone, order.Alamat,
+		order.Kecamatan, order.Desa, order.ProductName, order.Variant, order.Quantity, helper.Rupiah(order.Subtotal), helper.Rupiah(order.Discount),
+		helper.Rupiah(order.DeliveryFee), helper.Rupiah(order.Total), helper.Rupiah(order.AmountPaid), order.PaymentState, order.Status}
+}
+
+// ExportOrders streams the orders matching the search filters as CSV or XLSX.
+// The body is written after the handler returns, so failures past the first
+// row can only be logged.
+func (ctrl *ControllerImpl) ExportOrders(c *fiber.Ctx) error {
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+
+	filename := "pesanan-" + helper.Now().Format(domain.DateLayout)
+	var open func(w io.Writer) (helper.SheetWriter, error)
+	switch c.Query("format", "csv") {
+	case "csv":
+		c.Attachment(filename + ".csv")
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		open = func(w io.Writer) (helper.SheetWriter, error) { return helper.NewCSVSheet(w), nil }
+	case "xlsx":
+		c.Attachment(filename + ".xlsx")
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		open = func(w io.Writer) (helper.SheetWriter, error) { return helper.NewXLSXSheet(w, "Pesanan") }
+	default:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Unsupported format")
+	}
+
+	c.Status(fiber.StatusOK)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		defer w.Flush()
+
+		sheet, err := open(w)
+		if err != nil {
+			logger.GetLogger("controller-log").Log("export orders", "error", err.Error())
+			return
+		}
+		if err := sheet.WriteHeader(orderExportHeader...); err != nil {
+			logger.GetLogger("controller-log").Log("export orders", "error", err.Error())
+			return
+		}
+		err = ctrl.svc.ExportOrders(ctx, filter, func(order *domain.Orders) error {
+			return sheet.WriteRow(orderExportRow(order)...)
+		})
+		if err != nil {
+			logger.GetLogger("controller-log").Log("export orders", "error", err.Error())
+		}
+		if err := sheet.Close(); err != nil {
+			logger.GetLogger("controller-log").Log("export orders", "error", err.Error())
+		}
+	})
+	return nil
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderExportRow(t *testing.T) {
+	// 19 October 20:00 UTC is already the 20th in Jakarta.
+	createdAt := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	deliveryDate, _ := domain.ParseDate("2026-10-25")
+
+	tests := []struct {
+		name     string
+		order    *domain.Orders
+		expected map[string]string
+	}{
+		{
+			name: "full order",
+			order: &domain.Orders{InvoiceNumber: "INV/2026/10/0001", Code: "KH-7Q3F9", Id: "o-1", CreatedAt: &createdAt, DeliveryDate: &deliveryDate,
+				DeliverySlot: "siang", Name: "=HYPERLINK(\"http://x\")", Username: "user1", Phone: "+6281234567890", Alamat: "Jl. Mawar 1; RT 02",
+				Kecamatan: "Cibinong", Desa: "Pakansari", ProductName: "Nasi Box", Quantity: 10, Subtotal: 300000, Discount: 30000,
+				DeliveryFee: 15000, Total: 285000, AmountPaid: 100000, PaymentState: domain.PaymentStatePartial, Status: domain.OrderStatusConfirmed},
+			expected: map[string]string{
+				"No. Invoice":   "INV/2026/10/0001",
+				"Tanggal Pesan": "20 Oktober 2026",
+				"Tanggal Kirim": "25 Oktober 2026",
+				"Pemesan":       "'=HYPERLINK(\"http://x\")",
+				"Telepon":       "'+6281234567890",
+				"Alamat":        "Jl. Mawar 1; RT 02",
+				"Jumlah":        "10",
+				"Diskon":        helper.FormatRupiah(30000),
+				"Total":         helper.FormatRupiah(285000),
+				"Dibayar":       helper.FormatRupiah(100000),
+				"Status Bayar":  domain.PaymentStatePartial,
+			},
+		},
+		{
+			name:  "order without dates",
+			order: &domain.Orders{Id: "o-2", Name: "-Siti", Phone: "@siti", Quantity: 1},
+			expected: map[string]string{
+				"Tanggal Pesan": "",
+				"Tanggal Kirim": "",
+				"Pemesan":       "'-Siti",
+				"Telepon":       "'@siti",
+				"Total":         helper.FormatRupiah(0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := orderExportRow(tt.order)
+			assert.Len(t, row, len(orderExportHeader))
+
+			var buf bytes.Buffer
+			sheet := helper.NewCSVSheet(&buf)
+			assert.NoError(t, sheet.WriteHeader(orderExportHeader...))
+			assert.NoError(t, sheet.WriteRow(row...))
+			assert.NoError(t, sheet.Close())
+
+			reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\uFEFF")))
+			reader.Comma = ';'
+			records, err := reader.ReadAll()
+			assert.NoError(t, err)
+			if !assert.Len(t, records, 2) {
+				return
+			}
+			cells := map[string]string{}
+			for i, name := range records[0] {
+				cells[name] = records[1][i]
+			}
+			for name, value := range tt.expected {
+				assert.Equal(t, value, cells[name], name)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/service"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) SubmitFeedback(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.FeedbackRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Ratings must be between 1 and 5")
+	}
+
+	result, err := ctrl.svc.SubmitFeedback(ctx, c.Params("token"), &reqBody)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order not found")
+	case errors.Is(err, service.ErrFeedbackSubmitted):
+		return web.ErrorResponse(c, fiber.StatusConflict, "Conflict", err.Error())
+	case err != nil:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.Feedback](c, fiber.StatusCreated, "Feedback submitted successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetFeedback(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetFeedback(ctx, c.Query("status"), c.Query("product_id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load feedback")
+	}
+	return web.SuccessResponse[[]*domain.Feedback](c, fiber.StatusOK, "Feedback loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) ModerateFeedback(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.ModerateFeedbackRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Status must be approved or rejected")
+	}
+	username, _ := c.Locals("username").(string)
+
+	if err := ctrl.svc.ModerateFeedback(ctx, c.Params("id"), reqBody.Status, username); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to moderate feedback")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Feedback moderated successfully", nil)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetInvoice(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	invoice, err := ctrl.svc.GetInvoice(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load invoice")
+	}
+
+	doc := invoiceDocument(invoice)
+	if invoice.TrackingToken != "" {
+		link := trackingURL(c, invoice.TrackingToken)
+		doc.QRCode, err = helper.QRCodePNG(link, 256)
+		if err != nil {
+			return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to render invoice")
+		}
+		doc.QRCaption = "Pindai untuk melacak pesanan Anda:\n" + link
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WritePDF(&buf); err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to render invoice")
+	}
+	c.Type("pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.pdf"`, strings.ReplaceAll(invoice.Number, "/", "-")))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+func invoiceDocument(invoice *domain.Invoice) *helper.Document {
+	order := invoice.Order
+	summary := invoice.Summary
+	doc := &helper.Document{
+		Business: helper.BusinessFromEnv(),
+		Title:    "INVOICE",
+		Meta: []helper.DocumentField{
+			{Label: "Nomor", Value: invoice.Number},
+			{Label: "Kode Pesanan", Value: order.Code},
+			{Label: "Tanggal", Value: helper.FormatTanggal(invoice.IssuedAt)},
+		},
+		Recipient: []string{order.Name, order.Phone, order.Alamat, strings.Trim(order.Desa+", "+order.Kecamatan, ", ")},
+	}
+	if order.DeliveryDate != nil {
+		delivery := helper.FormatTanggal(order.DeliveryDate.Time)
+		if order.DeliverySlot != "" {
+			delivery += " " + order.DeliverySlot
+		}
+		doc.Meta = append(doc.Meta, helper.DocumentField{Label: "Pengiriman", Value: delivery})
+	}
+
+	unitPrice := 0.0
+	if order.Quantity > 0 {
+		unitPrice = order.Subtotal / float64(order.Quantity)
+	}
+	doc.Items = append(doc.Items, helper.DocumentItem{
+		Description: order.ProductName,
+		Detail:      order.Variant,
+		Quantity:    order.Quantity,
+		UnitPrice:   unitPrice,
+	})
+
+	doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Subtotal", Value: helper.FormatRupiah(order.Subtotal)})
+	if order.Discount > 0 {
+		label := "Diskon"
+		if order.VoucherCode != "" {
+			label += " (" + order.VoucherCode + ")"
+		}
+		doc.Totals = append(doc.Totals, helper.DocumentField{Label: label, Value: helper.FormatRupiah(-order.Discount)})
+	}
+	if order.DeliveryFee > 0 {
+		doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Ongkos kirim", Value: helper.FormatRupiah(order.DeliveryFee)})
+	}
+	doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Total", Value: helper.FormatRupiah(summary.Total), Bold: true})
+	if summary.Refunded > 0 {
+		doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Dikembalikan", Value: helper.FormatRupiah(summary.Refunded)})
+	}
+	doc.Totals = append(doc.Totals,
+		helper.DocumentField{Label: "Dibayar", Value: helper.FormatRupiah(summary.Paid)},
+		helper.DocumentField{Label: "Sisa tagihan", Value: helper.FormatRupiah(summary.Outstanding), Bold: true},
+	)
+
+	payments := helper.DocumentTable{
+		Title:  "Riwayat Pembayaran",
+		Header: []string{"Tanggal", "Metode", "Referensi", "Jumlah"},
+		Widths: []float64{40, 35, 65, 40},
+	}
+	for _, payment := range summary.Payments {
+		if payment.VoidedAt != nil {
+			continue
+		}
+		paidAt := ""
+		if payment.PaidAt != nil {
+			paidAt = helper.FormatTanggal(payment.PaidAt.In(helper.Location()))
+		}
+		payments.Rows = append(payments.Rows, []string{paidAt, payment.Method, payment.Reference, helper.FormatRupiah(payment.Amount)})
+	}
+	if len(payments.Rows) > 0 {
+		doc.Tables = append(doc.Tables, payments)
+	}
+	if summary.State == domain.PaymentStatePaid || summary.State == domain.PaymentStateOverpaid {
+		doc.Notes = append(doc.Notes, "LUNAS. Terima kasih atas pesanan Anda.")
+	} else {
+		doc.Notes = append(doc.Notes, "Mohon lunasi sisa tagihan sebelum tanggal pengiriman.")
+	}
+	return doc
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetOrderNotes(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	notes, err := ctrl.svc.GetOrderNotes(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to load order notes")
+	}
+	return web.SuccessResponse[[]*domain.OrderNote](c, fiber.StatusOK, "Order notes loaded successfully", notes)
+}
+
+func (ctrl *ControllerImpl) AddOrderNote(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.OrderNoteRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid note data")
+	}
+
+	username, _ := c.Locals("username").(string)
+	note, err := ctrl.svc.AddOrderNote(ctx, c.Params("id"), &reqBody, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.OrderNote](c, fiber.StatusCreated, "Order note added successfully", note)
+}
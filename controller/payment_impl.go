@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/gateway"
+	"khaira-admin/helper"
+	"khaira-admin/service"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) AddPayment(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.Payment
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid payment data")
+	}
+	reqBody.ReceivedBy, _ = c.Locals("username").(string)
+
+	result, err := ctrl.svc.AddPayment(ctx, c.Params("id"), &reqBody)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to record payment")
+	}
+	return web.SuccessResponse[*domain.Payment](c, fiber.StatusCreated, "Payment recorded successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetPayments(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetPaymentSummary(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load payments")
+	}
+	return web.SuccessResponse[*domain.PaymentSummary](c, fiber.StatusOK, "Payments loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) VoidPayment(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.VoidPaymentRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Void reason is required")
+	}
+	username, _ := c.Locals("username").(string)
+
+	if err := ctrl.svc.VoidPayment(ctx, c.Params("id"), username, reqBody.Reason); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to void payment")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Payment voided successfully", nil)
+}
+
+func (ctrl *ControllerImpl) CreatePaymentLink(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 15*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.CreatePaymentLink(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to create payment link")
+	}
+	return web.SuccessResponse[*domain.PaymentLink](c, fiber.StatusCreated, "Payment link created successfully", result)
+}
+
+func (ctrl *ControllerImpl) PaymentNotification(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	err := ctrl.svc.HandlePaymentNotification(ctx, c.Body())
+	switch {
+	case err == nil:
+		return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Notification processed successfully", nil)
+	case errors.Is(err, gateway.ErrInvalidSignature):
+		return web.ErrorResponse(c, fiber.StatusUnauthorized, "Unauthorized", "Invalid notification signature")
+	case errors.Is(err, sql.ErrNoRows):
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Unknown payment link")
+	default:
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to process notification")
+	}
+}
+
+// GetPaymentLinks lists payment links by ?status=, by default the ones held
+// for review because the provider collected another amount or the order was
+// cancelled.
+func (ctrl *ControllerImpl) GetPaymentLinks(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetPaymentLinks(ctx, c.Query("status"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load payment links")
+	}
+	return web.SuccessResponse[[]*domain.PaymentLink](c, fiber.StatusOK, "Payment links loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) ResolvePaymentLink(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.ResolvePaymentLinkRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Action must be record or dismiss")
+	}
+	username, _ := c.Locals("username").(string)
+
+	result, err := ctrl.svc.ResolvePaymentLink(ctx, c.Params("id"), reqBody.Action, username)
+	var invalid *service.ValidationError
+	switch {
+	case err == nil:
+		return web.SuccessResponse[*domain.PaymentLink](c, fiber.StatusOK, "Payment link resolved successfully", result)
+	case errors.Is(err, sql.ErrNoRows):
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Payment link not found")
+	case errors.As(err, &invalid):
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", invalid.Message)
+	default:
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to resolve payment link")
+	}
+}
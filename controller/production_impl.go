@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetDailyCapacities(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetDailyCapacities(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load capacities")
+	}
+	return web.SuccessResponse[[]*domain.DailyCapacity](c, fiber.StatusOK, "Capacities loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) SaveDailyCapacity(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.DailyCapacity
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid capacity data")
+	}
+	if err := ctrl.svc.SaveDailyCapacity(ctx, &reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to save capacity")
+	}
+	return web.SuccessResponse[*domain.DailyCapacity](c, fiber.StatusOK, "Capacity saved successfully", &reqBody)
+}
+
+func (ctrl *ControllerImpl) DeleteDailyCapacity(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	category := c.Params("category")
+	if err := ctrl.svc.DeleteDailyCapacity(ctx, category); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to delete capacity")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusNoContent, "Capacity deleted successfully", nil)
+}
+
+func (ctrl *ControllerImpl) GetProductionSettings(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetProductionSettings(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load production settings")
+	}
+	return web.SuccessResponse[*domain.ProductionSettings](c, fiber.StatusOK, "Production settings loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) UpdateProductionSettings(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.ProductionSettings
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid production settings")
+	}
+	if err := ctrl.svc.UpdateProductionSettings(ctx, &reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to update production settings")
+	}
+	return web.SuccessResponse[*domain.ProductionSettings](c, fiber.StatusOK, "Production settings updated successfully", &reqBody)
+}
+
+func (ctrl *ControllerImpl) GetBlackoutDates(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetBlackoutDates(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load blackout dates")
+	}
+	return web.SuccessResponse[[]*domain.BlackoutDate](c, fiber.StatusOK, "Blackout dates loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) AddBlackoutDate(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.BlackoutDate
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if reqBody.Date.IsZero() || helper.ValidateStruct(reqBody) != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid blackout date")
+	}
+	if err := ctrl.svc.AddBlackoutDate(ctx, &reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to add blackout date")
+	}
+	return web.SuccessResponse[*domain.BlackoutDate](c, fiber.StatusCreated, "Blackout date added successfully", &reqBody)
+}
+
+func (ctrl *ControllerImpl) DeleteBlackoutDate(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	date, err := domain.ParseDate(c.Params("date"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	if err := ctrl.svc.DeleteBlackoutDate(ctx, date); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to delete blackout date")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusNoContent, "Blackout date deleted successfully", nil)
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/service"
+	"khaira-admin/web"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) UploadPaymentProof(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.PaymentProof
+	amount, err := strconv.ParseFloat(c.FormValue("amount"), 64)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Amount must be a number")
+	}
+	reqBody.Amount = amount
+	reqBody.BankName = c.FormValue("bank_name")
+	reqBody.AccountName = c.FormValue("account_name")
+	reqBody.UploadedBy, _ = c.Locals("username").(string)
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid transfer proof data")
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Image is required")
+	}
+
+	result, err := ctrl.svc.UploadPaymentProof(ctx, c.Params("id"), &reqBody, file)
+	var invalid *service.ValidationError
+	if errors.As(err, &invalid) {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", invalid.Message)
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to upload transfer proof")
+	}
+	return web.SuccessResponse[*domain.PaymentProof](c, fiber.StatusCreated, "Transfer proof uploaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetPaymentProofs(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetPaymentProofs(ctx, c.Query("status"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load transfer proofs")
+	}
+	return web.SuccessResponse[[]*domain.PaymentProof](c, fiber.StatusOK, "Transfer proofs loaded successfully", result)
+}
+
+// GetPaymentProofFile sends the uploaded transfer proof. Proofs are bank
+// slips, so they are only served here behind the admin login and never from
+// the static image directory.
+func (ctrl *ControllerImpl) GetPaymentProofFile(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	path, err := ctrl.svc.GetPaymentProofFile(ctx, c.Params("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Transfer proof not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load transfer proof")
+	}
+	c.Set(fiber.HeaderContentDisposition, "inline")
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	if err := c.SendFile(path); err != nil {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Transfer proof file not found")
+	}
+	return nil
+}
+
+func (ctrl *ControllerImpl) ApprovePaymentProof(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	username, _ := c.Locals("username").(string)
+	result, err := ctrl.svc.ApprovePaymentProof(ctx, c.Params("id"), username)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to approve transfer proof")
+	}
+	return web.SuccessResponse[*domain.Payment](c, fiber.StatusOK, "Transfer proof approved successfully", result)
+}
+
+func (ctrl *ControllerImpl) RejectPaymentProof(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.RejectProofRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Reject reason is required")
+	}
+	username, _ := c.Locals("username").(string)
+
+	if err := ctrl.svc.RejectPaymentProof(ctx, c.Params("id"), username, reqBody.Reason); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to reject transfer proof")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Transfer proof rejected successfully", nil)
+}
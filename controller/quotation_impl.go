@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/service"
+	"khaira-admin/web"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) AddQuotation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.Quotation
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Incomplete quotation data")
+	}
+	reqBody.CreatedBy, _ = c.Locals("username").(string)
+
+	result, err := ctrl.svc.AddQuotation(ctx, &reqBody)
+	if err != nil {
+		return quotationError(c, err, "Failed to add quotation")
+	}
+	return web.SuccessResponse[*domain.Quotation](c, fiber.StatusCreated, "Quotation added successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetQuotations(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetQuotations(ctx, c.Query("status"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load quotations")
+	}
+	return web.SuccessResponse[[]*domain.Quotation](c, fiber.StatusOK, "Quotations loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetQuotation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	revision, err := strconv.Atoi(c.Query("revision", "0"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Revision must be a number")
+	}
+	result, err := ctrl.svc.GetQuotation(ctx, c.Params("id"), revision)
+	if err != nil {
+		return quotationError(c, err, "Failed to load quotation")
+	}
+	return web.SuccessResponse[*domain.Quotation](c, fiber.StatusOK, "Quotation loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) ReviseQuotation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.Quotation
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Incomplete quotation data")
+	}
+	reqBody.CreatedBy, _ = c.Locals("username").(string)
+
+	result, err := ctrl.svc.ReviseQuotation(ctx, c.Params("id"), &reqBody)
+	if err != nil {
+		return quotationError(c, err, "Failed to revise quotation")
+	}
+	return web.SuccessResponse[*domain.Quotation](c, fiber.StatusOK, "Quotation revised successfully", result)
+}
+
+func (ctrl *ControllerImpl) UpdateQuotationStatus(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.QuotationStatusRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid quotation status")
+	}
+	if err := ctrl.svc.UpdateQuotationStatus(ctx, c.Params("id"), reqBody.Status); err != nil {
+		return quotationError(c, err, "Failed to update quotation")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Quotation updated successfully", nil)
+}
+
+func (ctrl *ControllerImpl) ConvertQuotation(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 15*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.ConvertQuotation(ctx, c.Params("id"))
+	if err != nil {
+		return quotationError(c, err, "Failed to convert quotation")
+	}
+	return web.SuccessResponse[[]*domain.Orders](c, fiber.StatusCreated, "Quotation converted successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetQuotationPDF(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	revision, err := strconv.Atoi(c.Query("revision", "0"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Revision must be a number")
+	}
+	quotation, err := ctrl.svc.GetQuotation(ctx, c.Params("id"), revision)
+	if err != nil {
+		return quotationError(c, err, "Failed to load quotation")
+	}
+
+	var buf bytes.Buffer
+	if err := quotationDocument(quotation).WritePDF(&buf); err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to render quotation")
+	}
+	c.Type("pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="penawaran-%s-rev%d.pdf"`, quotation.Id[:8], quotation.Revision))
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+// quotationError shows the service's validation messages to the user and
+// hides everything else behind message.
+func quotationError(c *fiber.Ctx, err error, message string) error {
+	var invalid *service.ValidationError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Quotation not found")
+	case errors.As(err, &invalid):
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", invalid.Message)
+	default:
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", message)
+	}
+}
+
+func quotationDocument(quotation *domain.Quotation) *helper.Document {
+	doc := &helper.Document{
+		Business: helper.BusinessFromEnv(),
+		Title:    "PENAWARAN HARGA",
+		Meta: []helper.DocumentField{
+			{Label: "Nomor", Value: fmt.Sprintf("%s Rev.%d", strings.ToUpper(quotation.Id[:8]), quotation.Revision)},
+		},
+		Recipient: []string{quotation.Name, quotation.Phone, quotation.Alamat, strings.Trim(quotation.Desa+", "+quotation.Kecamatan, ", ")},
+	}
+	if quotation.CreatedAt != nil {
+		doc.Meta = append(doc.Meta, helper.DocumentField{Label: "Tanggal", Value: helper.FormatTanggal(quotation.CreatedAt.In(helper.Location()))})
+	}
+	if quotation.EventName != "" {
+		doc.Meta = append(doc.Meta, helper.DocumentField{Label: "Acara", Value: quotation.EventName})
+	}
+	if quotation.DeliveryDate != nil {
+		doc.Meta = append(doc.Meta, helper.DocumentField{Label: "Pengiriman", Value: helper.FormatTanggal(quotation.DeliveryDate.Time)})
+	}
+	if quotation.ValidUntil != nil {
+		doc.Meta = append(doc.Meta, helper.DocumentField{Label: "Berlaku s.d.", Value: helper.FormatTanggal(quotation.ValidUntil.Time)})
+	}
+	for _, item := range quotation.Items {
+		doc.Items = append(doc.Items, helper.DocumentItem{
+			Description: item.ProductName,
+			Detail:      item.Variant,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+		})
+	}
+	doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Subtotal", Value: helper.FormatRupiah(quotation.Subtotal)})
+	if quotation.DeliveryFee > 0 {
+		doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Ongkos kirim", Value: helper.FormatRupiah(quotation.DeliveryFee)})
+	}
+	doc.Totals = append(doc.Totals, helper.DocumentField{Label: "Total", Value: helper.FormatRupiah(quotation.Total), Bold: true})
+	if quotation.Notes != "" {
+		doc.Notes = append(doc.Notes, quotation.Notes)
+	}
+	return doc
+}
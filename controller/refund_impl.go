@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) RequestRefund(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.Refund
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid refund data")
+	}
+	reqBody.RequestedBy, _ = c.Locals("username").(string)
+
+	result, err := ctrl.svc.RequestRefund(ctx, c.Params("id"), &reqBody)
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Payment not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.Refund](c, fiber.StatusCreated, "Refund requested successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetRefunds(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetRefunds(ctx, c.Query("status"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load refunds")
+	}
+	return web.SuccessResponse[[]*domain.Refund](c, fiber.StatusOK, "Refunds loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetOrderRefunds(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetOrderRefunds(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load refunds")
+	}
+	return web.SuccessResponse[[]*domain.Refund](c, fiber.StatusOK, "Refunds loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) ApproveRefund(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	username, _ := c.Locals("username").(string)
+	result, err := ctrl.svc.ApproveRefund(ctx, c.Params("id"), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Refund not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.Refund](c, fiber.StatusOK, "Refund approved successfully", result)
+}
+
+func (ctrl *ControllerImpl) RejectRefund(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.RejectRefundRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Reject reason is required")
+	}
+	username, _ := c.Locals("username").(string)
+
+	err := ctrl.svc.RejectRefund(ctx, c.Params("id"), username, reqBody.Reason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Refund not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Refund rejected successfully", nil)
+}
+
+func (ctrl *ControllerImpl) GetCreditNotes(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetCreditNotes(ctx, c.Query("username"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load credit notes")
+	}
+	return web.SuccessResponse[[]*domain.CreditNote](c, fiber.StatusOK, "Credit notes loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) ApplyCreditNote(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.ApplyCreditNoteRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid credit note data")
+	}
+	username, _ := c.Locals("username").(string)
+
+	result, err := ctrl.svc.ApplyCreditNote(ctx, c.Params("id"), &reqBody, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order or credit note not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.Payment](c, fiber.StatusCreated, "Credit note applied successfully", result)
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetRegions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetRegions(ctx, c.Query("parent"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", err.Error())
+	}
+	return web.SuccessResponse[[]*domain.Region](c, fiber.StatusOK, "Regions loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetRegion(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetRegion(ctx, c.Params("code"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", err.Error())
+	}
+	return web.SuccessResponse[*domain.Region](c, fiber.StatusOK, "Region found", result)
+}
+
+func (ctrl *ControllerImpl) SearchRegions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	limit := c.QueryInt("limit", 20)
+	result, err := ctrl.svc.SearchRegions(ctx, c.Query("q"), c.Query("level"), c.Query("parent"), limit)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[[]*domain.Region](c, fiber.StatusOK, "Regions loaded successfully", result)
+}
@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func reportDate(c *fiber.Ctx) (domain.Date, error) {
+	value := c.Query("date")
+	if value == "" {
+		tomorrow := helper.Now().AddDate(0, 0, 1)
+		return domain.ParseDate(tomorrow.Format(domain.DateLayout))
+	}
+	return domain.ParseDate(value)
+}
+
+func sendReport(c *fiber.Ctx, report *helper.Report, format string, filename string) error {
+	var buf bytes.Buffer
+	switch format {
+	case "csv":
+		if err := report.WriteCSV(&buf); err != nil {
+			return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to render report")
+		}
+		c.Attachment(filename + ".csv")
+	case "pdf":
+		if err := report.WritePDF(&buf); err != nil {
+			return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to render report")
+		}
+		c.Type("pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.pdf"`, filename))
+	default:
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Unsupported format")
+	}
+	return c.Status(fiber.StatusOK).Send(buf.Bytes())
+}
+
+func (ctrl *ControllerImpl) GetProductionSheet(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	date, err := reportDate(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	sheet, err := ctrl.svc.GetProductionSheet(ctx, date)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load production sheet")
+	}
+
+	format := c.Query("format", "json")
+	if format == "json" {
+		return web.SuccessResponse[*domain.ProductionSheet](c, fiber.StatusOK, "Production sheet loaded successfully", sheet)
+	}
+	return sendReport(c, productionReport(sheet), format, "produksi-"+date.String())
+}
+
+func productionReport(sheet *domain.ProductionSheet) *helper.Report {
+	report := &helper.Report{
+		Title:    "Lembar Produksi " + sheet.Date.String(),
+		Subtitle: fmt.Sprintf("%d porsi dari %d pesanan", sheet.TotalPortions, sheet.TotalOrders),
+		Header:   []string{"Produk", "Varian", "Kategori", "Pesanan", "Pemesan", "Catatan", "Porsi"},
+		Widths:   []float64{35, 18, 20, 32, 30, 40, 15},
+	}
+	for _, item := range sheet.Items {
+		report.Rows = append(report.Rows, helper.ReportRow{
+			Cells: []string{item.ProductName, item.Variant, item.Category, "", "", "", strconv.Itoa(item.Quantity)},
+			Bold:  true,
+		})
+		for _, order := range item.Orders {
+			report.Rows = append(report.Rows, helper.ReportRow{
+				Cells: []string{item.ProductName, item.Variant, item.Category, productionOrderRef(order), order.Name, reportNotes(order.CustomerNote, order.Notes), strconv.Itoa(order.Quantity)},
+			})
+		}
+	}
+	report.Rows = append(report.Rows, helper.ReportRow{
+		Cells: []string{"Total", "", "", "", "", "", strconv.Itoa(sheet.TotalPortions)},
+		Bold:  true,
+	})
+	return report
+}
+
+// productionOrderRef is what the kitchen sees for an order: the short order
+// code, or the invoice number for orders made before codes were assigned.
+func productionOrderRef(order *domain.ProductionOrder) string {
+	if order.Code != "" {
+		return order.Code
+	}
+	if order.InvoiceNumber != "" {
+		return order.InvoiceNumber
+	}
+	return order.OrderId
+}
+
+func (ctrl *ControllerImpl) GetDeliveryManifest(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	date, err := reportDate(c)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	manifest, err := ctrl.svc.GetDeliveryManifest(ctx, date)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load delivery manifest")
+	}
+
+	format := c.Query("format", "json")
+	if format == "json" {
+		return web.SuccessResponse[*domain.DeliveryManifest](c, fiber.StatusOK, "Delivery manifest loaded successfully", manifest)
+	}
+	return sendReport(c, manifestReport(manifest), format, "manifest-"+date.String())
+}
+
+func manifestReport(manifest *domain.DeliveryManifest) *helper.Report {
+	report := &helper.Report{
+		Title:    "Manifest Pengiriman " + manifest.Date.String(),
+		Subtitle: fmt.Sprintf("%d pesanan, total tagihan %s", manifest.TotalOrders, helper.FormatRupiah(manifest.TotalToCollect)),
+		Header:   []string{"Kecamatan", "Desa", "Jam", "Pemesan", "Telepon", "Alamat", "Pesanan", "Catatan", "Tagihan"},
+		Widths:   []float64{18, 18, 12, 22, 22, 32, 22, 26, 18},
+	}
+	for _, area := range manifest.Areas {
+		report.Rows = append(report.Rows, helper.ReportRow{
+			Cells: []string{area.Kecamatan, area.Desa, "", "", "", "", fmt.Sprintf("%d pesanan", len(area.Stops)), "", helper.FormatRupiah(area.TotalToCollect)},
+			Bold:  true,
+		})
+		for _, stop := range area.Stops {
+			report.Rows = append(report.Rows, helper.ReportRow{
+				Cells: []string{area.Kecamatan, area.Desa, stop.DeliverySlot, stop.Name, stop.Phone, stop.Alamat, manifestItems(stop), reportNotes(stop.CustomerNote, stop.Notes), helper.FormatRupiah(stop.AmountToCollect)},
+			})
+		}
+	}
+	return report
+}
+
+func manifestItems(stop *domain.ManifestStop) string {
+	if stop.Variant != "" {
+		return fmt.Sprintf("%dx %s (%s)", stop.Quantity, stop.ProductName, stop.Variant)
+	}
+	return fmt.Sprintf("%dx %s", stop.Quantity, stop.ProductName)
+}
+
+// reportNotes puts the customer's note and the internal notes of an order in
+// a single report cell, labelled so the kitchen and drivers can tell what the
+// customer asked for from what the admins wrote.
+func reportNotes(customerNote string, notes []string) string {
+	var parts []string
+	if customerNote != "" {
+		parts = append(parts, "Pelanggan: "+customerNote)
+	}
+	if len(notes) > 0 {
+		parts = append(parts, "Internal: "+strings.Join(notes, "; "))
+	}
+	return strings.Join(parts, " | ")
+}
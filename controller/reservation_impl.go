@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetReservationPolicies(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	policies, err := ctrl.svc.GetReservationPolicies(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to load reservation policies")
+	}
+	return web.SuccessResponse[[]*domain.ReservationPolicy](c, fiber.StatusOK, "Reservation policies loaded successfully", policies)
+}
+
+func (ctrl *ControllerImpl) SaveReservationPolicy(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.ReservationPolicy
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid reservation policy data")
+	}
+	if err := ctrl.svc.SaveReservationPolicy(ctx, &reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to save reservation policy")
+	}
+	return web.SuccessResponse[*domain.ReservationPolicy](c, fiber.StatusOK, "Reservation policy saved successfully", &reqBody)
+}
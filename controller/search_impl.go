@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var orderSorts = []string{domain.OrderSortCreatedAt, domain.OrderSortDeliveryDate, domain.OrderSortTotal}
+
+// limitAll is the limit value that asks for every order instead of a page.
+const limitAll = "all"
+
+// parseOrderFilter reads the order search query parameters. status may be
+// repeated or comma separated; sort takes a field name, prefixed with "-" for
+// descending order.
+func parseOrderFilter(c *fiber.Ctx) (*domain.OrderFilter, error) {
+	filter := &domain.OrderFilter{
+		Kecamatan: strings.TrimSpace(c.Query("kecamatan")),
+		Desa:      strings.TrimSpace(c.Query("desa")),
+		Username:  strings.TrimSpace(c.Query("username")),
+		Phone:     helper.PhoneSearchTerm(c.Query("phone")),
+		ProductId: strings.TrimSpace(c.Query("product_id")),
+		Query:     strings.TrimSpace(c.Query("q")),
+	}
+	for _, value := range c.Context().QueryArgs().PeekMulti("status") {
+		for _, status := range strings.Split(string(value), ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseDayQuery(c, "created_from", 0); err != nil {
+		return nil, err
+	}
+	if filter.CreatedTo, err = parseDayQuery(c, "created_to", 1); err != nil {
+		return nil, err
+	}
+	if filter.DeliveryFrom, err = parseDateQuery(c, "delivery_from"); err != nil {
+		return nil, err
+	}
+	if filter.DeliveryTo, err = parseDateQuery(c, "delivery_to"); err != nil {
+		return nil, err
+	}
+	if filter.MinTotal, err = parseAmountQuery(c, "min_total"); err != nil {
+		return nil, err
+	}
+	if filter.MaxTotal, err = parseAmountQuery(c, "max_total"); err != nil {
+		return nil, err
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		filter.SortBy = strings.TrimPrefix(sort, "-")
+		filter.Descending = strings.HasPrefix(sort, "-")
+		if !slices.Contains(orderSorts, filter.SortBy) {
+			return nil, fmt.Errorf("sort must be one of %s", strings.Join(orderSorts, ", "))
+		}
+	}
+	if limit := c.Query("limit"); limit != "" && limit != limitAll {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit <= 0 {
+			return nil, fmt.Errorf("limit must be a positive number")
+		}
+	}
+	return filter, nil
+}
+
+func parseDateQuery(c *fiber.Ctx, key string) (*domain.Date, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := domain.ParseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must use the format %s", key, domain.DateLayout)
+	}
+	return &date, nil
+}
+
+// parseDayQuery returns the start of the given day in the business time zone,
+// shifted by offset days so that an inclusive "to" date becomes an exclusive
+// bound.
+func parseDayQuery(c *fiber.Ctx, key string, offset int) (*time.Time, error) {
+	date, err := parseDateQuery(c, key)
+	if err != nil || date == nil {
+		return nil, err
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day()+offset, 0, 0, 0, 0, helper.Location())
+	return &day, nil
+}
+
+func parseAmountQuery(c *fiber.Ctx, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("%s must be a positive number", key)
+	}
+	return &amount, nil
+}
+
+// parseOrderExpand reads ?expand=product,customer,payments,history.
+func parseOrderExpand(c *fiber.Ctx) (domain.OrderExpand, error) {
+	var expand domain.OrderExpand
+	for _, relation := range strings.Split(c.Query("expand"), ",") {
+		switch strings.TrimSpace(relation) {
+		case "":
+		case "product":
+			expand.Product = true
+		case "customer":
+			expand.Customer = true
+		case "payments":
+			expand.Payments = true
+		case "history":
+			expand.History = true
+		case "notes":
+			expand.Notes = true
+		default:
+			return expand, fmt.Errorf("unknown expand relation %q", relation)
+		}
+	}
+	return expand, nil
+}
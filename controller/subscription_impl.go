@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) AddSubscription(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody domain.Subscription
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Incomplete subscription data")
+	}
+	result, err := ctrl.svc.AddSubscription(ctx, &reqBody)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[*domain.Subscription](c, fiber.StatusCreated, "Subscription added successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetSubscriptions(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetSubscriptions(ctx, c.Query("status"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load subscriptions")
+	}
+	return web.SuccessResponse[[]*domain.Subscription](c, fiber.StatusOK, "Subscriptions loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) GetSubscription(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetSubscription(ctx, c.Params("id"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Subscription not found")
+	}
+	return web.SuccessResponse[*domain.Subscription](c, fiber.StatusOK, "Subscription loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) PauseSubscription(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.PauseSubscriptionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&reqBody); err != nil {
+			return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+		}
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid pause date")
+	}
+	var until *domain.Date
+	if reqBody.Until != "" {
+		date, err := domain.ParseDate(reqBody.Until)
+		if err != nil {
+			return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+		}
+		until = &date
+	}
+	if err := ctrl.svc.PauseSubscription(ctx, c.Params("id"), until); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Subscription paused successfully", nil)
+}
+
+func (ctrl *ControllerImpl) ResumeSubscription(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.ResumeSubscription(ctx, c.Params("id")); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Subscription resumed successfully", nil)
+}
+
+func (ctrl *ControllerImpl) CancelSubscription(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.CancelSubscription(ctx, c.Params("id")); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Subscription cancelled successfully", nil)
+}
+
+func (ctrl *ControllerImpl) SkipSubscriptionDate(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	var reqBody web.SkipDateRequest
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid skip date")
+	}
+	date, err := domain.ParseDate(reqBody.Date)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	if err := ctrl.svc.SkipSubscriptionDate(ctx, c.Params("id"), date); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Delivery skipped successfully", nil)
+}
+
+func (ctrl *ControllerImpl) UnskipSubscriptionDate(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	date, err := domain.ParseDate(c.Params("date"))
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	if err := ctrl.svc.UnskipSubscriptionDate(ctx, c.Params("id"), date); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to restore delivery")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Delivery restored successfully", nil)
+}
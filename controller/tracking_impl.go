@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// trackingURL falls back to the public tracking endpoint of this API when no
+// storefront page is configured.
+func trackingURL(c *fiber.Ctx, token string) string {
+	return helper.TrackingURL(c.BaseURL()+"/v1/track", token)
+}
+
+func (ctrl *ControllerImpl) TrackOrder(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	tracking, err := ctrl.svc.GetOrderTracking(ctx, c.Params("token"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to load order tracking")
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return web.SuccessResponse[*domain.OrderTracking](c, fiber.StatusOK, "Order tracking loaded successfully", tracking)
+}
+
+func (ctrl *ControllerImpl) GetTrackingLink(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	token, err := ctrl.svc.GetTrackingToken(ctx, c.Params("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to load tracking link")
+	}
+	link := &domain.TrackingLink{Token: token, URL: trackingURL(c, token)}
+	return web.SuccessResponse[*domain.TrackingLink](c, fiber.StatusOK, "Tracking link loaded successfully", link)
+}
+
+func (ctrl *ControllerImpl) GetTrackingQRCode(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	token, err := ctrl.svc.GetTrackingToken(ctx, c.Params("id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		return web.ErrorResponse(c, fiber.StatusNotFound, "Not Found", "Order not found")
+	}
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to load tracking link")
+	}
+	size := min(max(c.QueryInt("size", 256), 64), 1024)
+	png, err := helper.QRCodePNG(trackingURL(c, token), size)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusInternalServerError, "Internal Server Error", "Failed to render QR code")
+	}
+	c.Type("png")
+	return c.Status(fiber.StatusOK).Send(png)
+}
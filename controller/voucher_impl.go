@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/service"
+	"khaira-admin/web"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (ctrl *ControllerImpl) GetVouchers(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetVouchers(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load vouchers")
+	}
+	return web.SuccessResponse[[]*domain.Voucher](c, fiber.StatusOK, "Vouchers loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) AddVoucher(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	reqBody := domain.Voucher{Active: true}
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid voucher data")
+	}
+	result, err := ctrl.svc.AddVoucher(ctx, &reqBody)
+	if err != nil {
+		return voucherError(c, err, "Failed to add voucher")
+	}
+	return web.SuccessResponse[*domain.Voucher](c, fiber.StatusCreated, "Voucher added successfully", result)
+}
+
+func (ctrl *ControllerImpl) UpdateVoucher(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	reqBody := domain.Voucher{Active: true}
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid voucher data")
+	}
+	if err := ctrl.svc.UpdateVoucher(ctx, &reqBody, c.Params("id")); err != nil {
+		return voucherError(c, err, "Failed to update voucher")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Voucher updated successfully", nil)
+}
+
+// voucherError shows the rule a voucher or discount rule broke and falls back
+// to message for anything else.
+func voucherError(c *fiber.Ctx, err error, message string) error {
+	var invalid *service.ValidationError
+	if errors.As(err, &invalid) {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", invalid.Message)
+	}
+	return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", message)
+}
+
+func (ctrl *ControllerImpl) DeactivateVoucher(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeactivateVoucher(ctx, c.Params("id")); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to deactivate voucher")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Voucher deactivated successfully", nil)
+}
+
+func (ctrl *ControllerImpl) GetDiscountRules(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.svc.GetDiscountRules(ctx)
+	if err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to load discount rules")
+	}
+	return web.SuccessResponse[[]*domain.DiscountRule](c, fiber.StatusOK, "Discount rules loaded successfully", result)
+}
+
+func (ctrl *ControllerImpl) AddDiscountRule(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	reqBody := domain.DiscountRule{Active: true}
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid discount rule data")
+	}
+	result, err := ctrl.svc.AddDiscountRule(ctx, &reqBody)
+	if err != nil {
+		return voucherError(c, err, "Failed to add discount rule")
+	}
+	return web.SuccessResponse[*domain.DiscountRule](c, fiber.StatusCreated, "Discount rule added successfully", result)
+}
+
+func (ctrl *ControllerImpl) UpdateDiscountRule(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	reqBody := domain.DiscountRule{Active: true}
+	if err := c.BodyParser(&reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid request body")
+	}
+	if err := helper.ValidateStruct(reqBody); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Invalid discount rule data")
+	}
+	if err := ctrl.svc.UpdateDiscountRule(ctx, &reqBody, c.Params("id")); err != nil {
+		return voucherError(c, err, "Failed to update discount rule")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Discount rule updated successfully", nil)
+}
+
+func (ctrl *ControllerImpl) DeleteDiscountRule(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.svc.DeleteDiscountRule(ctx, c.Params("id")); err != nil {
+		return web.ErrorResponse(c, fiber.StatusBadRequest, "Bad Request", "Failed to delete discount rule")
+	}
+	return web.SuccessResponse[interface{}](c, fiber.StatusOK, "Discount rule deleted successfully", nil)
+}
@@ -0,0 +1,9 @@
+DROP TABLE blackout_dates;
+DROP TABLE production_settings;
+DROP TABLE daily_capacities;
+
+DROP INDEX idx_orders_delivery_date ON orders;
+DROP INDEX idx_products_category ON products;
+
+ALTER TABLE orders DROP COLUMN delivery_date;
+ALTER TABLE products DROP COLUMN category;
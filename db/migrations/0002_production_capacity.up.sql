@@ -0,0 +1,23 @@
+ALTER TABLE products ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT 'umum' AFTER price;
+ALTER TABLE orders ADD COLUMN delivery_date DATE NULL AFTER status;
+
+CREATE TABLE daily_capacities (
+    category VARCHAR(50) PRIMARY KEY,
+    max_portions INT NOT NULL
+);
+
+CREATE TABLE production_settings (
+    id TINYINT PRIMARY KEY,
+    cutoff_days INT NOT NULL,
+    cutoff_time CHAR(5) NOT NULL
+);
+
+CREATE TABLE blackout_dates (
+    date DATE PRIMARY KEY,
+    reason VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_products_category ON products(category);
+CREATE INDEX idx_orders_delivery_date ON orders(delivery_date);
+
+INSERT INTO production_settings (id, cutoff_days, cutoff_time) VALUES (1, 1, '15:00');
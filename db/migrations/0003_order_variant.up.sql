@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN variant VARCHAR(50) NOT NULL DEFAULT '' AFTER product_name;
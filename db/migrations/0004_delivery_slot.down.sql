@@ -0,0 +1,3 @@
+DROP INDEX idx_orders_delivery_area ON orders;
+
+ALTER TABLE orders DROP COLUMN delivery_slot;
@@ -0,0 +1,3 @@
+ALTER TABLE orders ADD COLUMN delivery_slot VARCHAR(20) NOT NULL DEFAULT '' AFTER delivery_date;
+
+CREATE INDEX idx_orders_delivery_area ON orders(delivery_date, kecamatan, desa);
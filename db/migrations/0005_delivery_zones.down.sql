@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN delivery_fee;
+ALTER TABLE orders DROP COLUMN subtotal;
+
+DROP TABLE delivery_zones;
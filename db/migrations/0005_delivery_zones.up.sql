@@ -0,0 +1,16 @@
+CREATE TABLE delivery_zones (
+    id CHAR(36) PRIMARY KEY,
+    kecamatan VARCHAR(100) NOT NULL,
+    desa VARCHAR(100) NOT NULL DEFAULT '',
+    fee INT NOT NULL DEFAULT 0,
+    min_order INT NOT NULL DEFAULT 0,
+    deliverable BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_delivery_zones_area (kecamatan, desa)
+);
+
+ALTER TABLE orders ADD COLUMN subtotal DOUBLE NOT NULL DEFAULT 0 AFTER quantity;
+ALTER TABLE orders ADD COLUMN delivery_fee DOUBLE NOT NULL DEFAULT 0 AFTER subtotal;
+
+UPDATE orders SET subtotal = total;
@@ -0,0 +1,4 @@
+DROP INDEX idx_orders_region ON orders;
+
+ALTER TABLE orders DROP COLUMN desa_code;
+ALTER TABLE orders DROP COLUMN kecamatan_code;
@@ -0,0 +1,4 @@
+ALTER TABLE orders ADD COLUMN kecamatan_code VARCHAR(13) NOT NULL DEFAULT '' AFTER kecamatan;
+ALTER TABLE orders ADD COLUMN desa_code VARCHAR(13) NOT NULL DEFAULT '' AFTER desa;
+
+CREATE INDEX idx_orders_region ON orders(kecamatan_code, desa_code);
@@ -0,0 +1,17 @@
+CREATE TABLE payments (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    amount DOUBLE NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    proof VARCHAR(255) NOT NULL DEFAULT '',
+    received_by VARCHAR(100) NOT NULL,
+    paid_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    voided_at TIMESTAMP NULL,
+    voided_by VARCHAR(100) NOT NULL DEFAULT '',
+    void_reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_payments_order ON payments(order_id, voided_at);
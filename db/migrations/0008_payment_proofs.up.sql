@@ -0,0 +1,19 @@
+CREATE TABLE payment_proofs (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    amount DOUBLE NOT NULL,
+    bank_name VARCHAR(50) NOT NULL DEFAULT '',
+    account_name VARCHAR(100) NOT NULL DEFAULT '',
+    image VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    uploaded_by VARCHAR(100) NOT NULL,
+    reviewed_by VARCHAR(100) NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP NULL,
+    reject_reason VARCHAR(255) NOT NULL DEFAULT '',
+    payment_id CHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_payment_proofs_status ON payment_proofs(status, created_at);
+CREATE INDEX idx_payment_proofs_order ON payment_proofs(order_id);
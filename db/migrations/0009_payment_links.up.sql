@@ -0,0 +1,16 @@
+CREATE TABLE payment_links (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    provider_order_id VARCHAR(50) NOT NULL,
+    amount DOUBLE NOT NULL,
+    token VARCHAR(255) NOT NULL DEFAULT '',
+    redirect_url VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    transaction_id VARCHAR(100) NOT NULL DEFAULT '',
+    payment_id CHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_payment_links_provider_order (provider, provider_order_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX uq_orders_invoice_number ON orders;
+ALTER TABLE orders DROP COLUMN invoice_number;
+DROP TABLE invoice_sequences;
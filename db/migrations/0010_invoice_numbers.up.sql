@@ -0,0 +1,7 @@
+CREATE TABLE invoice_sequences (
+    period CHAR(7) PRIMARY KEY,
+    last_number INT NOT NULL
+);
+
+ALTER TABLE orders ADD COLUMN invoice_number VARCHAR(20) NULL;
+CREATE UNIQUE INDEX uq_orders_invoice_number ON orders(invoice_number);
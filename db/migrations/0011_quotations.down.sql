@@ -0,0 +1,3 @@
+DROP TABLE quotation_items;
+DROP TABLE quotation_revisions;
+DROP TABLE quotations;
@@ -0,0 +1,45 @@
+CREATE TABLE quotations (
+    id CHAR(36) PRIMARY KEY,
+    revision INT NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    event_name VARCHAR(100) NOT NULL DEFAULT '',
+    name VARCHAR(100) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    alamat VARCHAR(255) NOT NULL,
+    kecamatan VARCHAR(100) NOT NULL,
+    desa VARCHAR(100) NOT NULL,
+    delivery_date DATE NOT NULL,
+    delivery_slot VARCHAR(20) NOT NULL DEFAULT '',
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (username) REFERENCES users(username)
+);
+
+CREATE TABLE quotation_revisions (
+    quotation_id CHAR(36) NOT NULL,
+    revision INT NOT NULL,
+    delivery_fee DOUBLE NOT NULL DEFAULT 0,
+    valid_until DATE NOT NULL,
+    notes TEXT,
+    created_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (quotation_id, revision),
+    FOREIGN KEY (quotation_id) REFERENCES quotations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE quotation_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    quotation_id CHAR(36) NOT NULL,
+    revision INT NOT NULL,
+    product_id VARCHAR(6) NOT NULL,
+    product_name VARCHAR(100) NOT NULL,
+    variant VARCHAR(50) NOT NULL DEFAULT '',
+    quantity INT NOT NULL,
+    unit_price DOUBLE NOT NULL,
+    order_id CHAR(36) NULL,
+    FOREIGN KEY (quotation_id, revision) REFERENCES quotation_revisions(quotation_id, revision) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_quotations_status ON quotations(status, created_at);
@@ -0,0 +1,6 @@
+ALTER TABLE orders DROP COLUMN voucher_code;
+ALTER TABLE orders DROP COLUMN discount;
+
+DROP TABLE discount_rules;
+DROP TABLE voucher_redemptions;
+DROP TABLE vouchers;
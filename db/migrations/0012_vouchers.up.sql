@@ -0,0 +1,51 @@
+CREATE TABLE vouchers (
+    id CHAR(36) PRIMARY KEY,
+    code VARCHAR(30) NOT NULL UNIQUE,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL,
+    value DOUBLE NOT NULL,
+    max_discount DOUBLE NOT NULL DEFAULT 0,
+    min_order DOUBLE NOT NULL DEFAULT 0,
+    usage_limit INT NOT NULL DEFAULT 0,
+    per_customer_limit INT NOT NULL DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    product_ids VARCHAR(255) NOT NULL DEFAULT '',
+    categories VARCHAR(255) NOT NULL DEFAULT '',
+    valid_from TIMESTAMP NULL,
+    valid_until TIMESTAMP NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+CREATE TABLE voucher_redemptions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    voucher_id CHAR(36) NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    discount DOUBLE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_voucher_redemptions_order (voucher_id, order_id),
+    FOREIGN KEY (voucher_id) REFERENCES vouchers(id),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_voucher_redemptions_customer ON voucher_redemptions(voucher_id, username);
+
+CREATE TABLE discount_rules (
+    id CHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    min_quantity INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    value DOUBLE NOT NULL,
+    product_id VARCHAR(6) NOT NULL DEFAULT '',
+    category VARCHAR(50) NOT NULL DEFAULT '',
+    valid_from TIMESTAMP NULL,
+    valid_until TIMESTAMP NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+ALTER TABLE orders ADD COLUMN discount DOUBLE NOT NULL DEFAULT 0 AFTER subtotal;
+ALTER TABLE orders ADD COLUMN voucher_code VARCHAR(30) NOT NULL DEFAULT '' AFTER discount;
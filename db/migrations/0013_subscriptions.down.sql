@@ -0,0 +1,3 @@
+DROP TABLE subscription_runs;
+DROP TABLE subscription_skips;
+DROP TABLE subscriptions;
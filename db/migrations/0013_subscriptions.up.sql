@@ -0,0 +1,42 @@
+CREATE TABLE subscriptions (
+    id CHAR(36) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    alamat VARCHAR(255) NOT NULL,
+    kecamatan VARCHAR(100) NOT NULL,
+    desa VARCHAR(100) NOT NULL,
+    product_id VARCHAR(6) NOT NULL,
+    variant VARCHAR(50) NOT NULL DEFAULT '',
+    quantity INT NOT NULL,
+    delivery_slot VARCHAR(20) NOT NULL DEFAULT '',
+    days_of_week VARCHAR(20) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    paused_until DATE NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (username) REFERENCES users(username),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX idx_subscriptions_status ON subscriptions(status, start_date);
+
+CREATE TABLE subscription_skips (
+    subscription_id CHAR(36) NOT NULL,
+    date DATE NOT NULL,
+    PRIMARY KEY (subscription_id, date),
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE subscription_runs (
+    subscription_id CHAR(36) NOT NULL,
+    delivery_date DATE NOT NULL,
+    order_id CHAR(36) NULL,
+    error VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (subscription_id, delivery_date),
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+DROP INDEX idx_orders_reservation ON orders;
+
+ALTER TABLE orders DROP COLUMN reserved_until;
+ALTER TABLE orders DROP COLUMN payment_method;
+
+DROP TABLE reservation_policies;
@@ -0,0 +1,16 @@
+CREATE TABLE reservation_policies (
+    payment_method VARCHAR(20) PRIMARY KEY,
+    ttl_minutes INT NOT NULL
+);
+
+INSERT INTO reservation_policies (payment_method, ttl_minutes) VALUES
+('', 1440),
+('cash', 0),
+('transfer', 1440),
+('qris', 60),
+('gateway', 120);
+
+ALTER TABLE orders ADD COLUMN payment_method VARCHAR(20) NOT NULL DEFAULT '' AFTER total;
+ALTER TABLE orders ADD COLUMN reserved_until TIMESTAMP NULL AFTER payment_method;
+
+CREATE INDEX idx_orders_reservation ON orders(status, reserved_until);
@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN courier;
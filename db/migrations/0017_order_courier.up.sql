@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN courier VARCHAR(100) NOT NULL DEFAULT '' AFTER delivery_slot;
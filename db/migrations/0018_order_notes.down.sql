@@ -0,0 +1,2 @@
+DROP TABLE order_notes;
+ALTER TABLE orders DROP COLUMN customer_note;
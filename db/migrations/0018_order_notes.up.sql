@@ -0,0 +1,14 @@
+ALTER TABLE orders ADD COLUMN customer_note VARCHAR(500) NOT NULL DEFAULT '' AFTER courier;
+
+CREATE TABLE order_notes (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    parent_id CHAR(36) NULL,
+    author VARCHAR(100) NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES order_notes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_notes_order ON order_notes(order_id, created_at);
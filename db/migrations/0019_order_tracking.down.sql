@@ -0,0 +1,2 @@
+DROP INDEX uq_orders_tracking_token ON orders;
+ALTER TABLE orders DROP COLUMN tracking_token;
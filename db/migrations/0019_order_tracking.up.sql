@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN tracking_token CHAR(43) NULL;
+CREATE UNIQUE INDEX uq_orders_tracking_token ON orders(tracking_token);
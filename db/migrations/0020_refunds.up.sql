@@ -0,0 +1,34 @@
+CREATE TABLE credit_notes (
+    id CHAR(36) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    order_id CHAR(36) NOT NULL,
+    amount DOUBLE NOT NULL,
+    balance DOUBLE NOT NULL,
+    issued_by VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_credit_notes_username ON credit_notes(username, created_at);
+
+CREATE TABLE refunds (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    payment_id CHAR(36) NOT NULL,
+    amount DOUBLE NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    requested_by VARCHAR(100) NOT NULL,
+    reviewed_by VARCHAR(100) NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP NULL,
+    reject_reason VARCHAR(255) NOT NULL DEFAULT '',
+    credit_note_id CHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (payment_id) REFERENCES payments(id)
+);
+
+CREATE INDEX idx_refunds_status ON refunds(status, created_at);
+CREATE INDEX idx_refunds_order ON refunds(order_id);
+CREATE INDEX idx_refunds_payment ON refunds(payment_id, status);
@@ -0,0 +1,17 @@
+CREATE TABLE feedback (
+    id CHAR(36) PRIMARY KEY,
+    order_id CHAR(36) NOT NULL UNIQUE,
+    product_id VARCHAR(6) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    rating TINYINT NOT NULL,
+    product_rating TINYINT NOT NULL,
+    comment VARCHAR(1000) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    moderated_by VARCHAR(100) NOT NULL DEFAULT '',
+    moderated_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX idx_feedback_status ON feedback(status, created_at);
+CREATE INDEX idx_feedback_product ON feedback(product_id, status);
@@ -0,0 +1,2 @@
+DROP INDEX uq_orders_code ON orders;
+ALTER TABLE orders DROP COLUMN code;
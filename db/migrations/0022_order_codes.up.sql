@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN code VARCHAR(8) NULL;
+CREATE UNIQUE INDEX uq_orders_code ON orders(code);
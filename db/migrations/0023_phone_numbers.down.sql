@@ -0,0 +1,5 @@
+UPDATE orders SET phone = CONCAT('0', SUBSTRING(phone, 4)) WHERE phone LIKE '+62%';
+UPDATE quotations SET phone = CONCAT('0', SUBSTRING(phone, 4)) WHERE phone LIKE '+62%';
+UPDATE subscriptions SET phone = CONCAT('0', SUBSTRING(phone, 4)) WHERE phone LIKE '+62%';
+UPDATE orders SET phone = LEFT(phone, 12);
+ALTER TABLE orders MODIFY phone CHAR(12) NOT NULL;
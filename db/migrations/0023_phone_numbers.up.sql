@@ -0,0 +1,16 @@
+ALTER TABLE orders MODIFY phone VARCHAR(16) NOT NULL;
+
+UPDATE orders SET phone = REPLACE(REPLACE(REPLACE(phone, ' ', ''), '-', ''), '.', '');
+UPDATE orders SET phone = CONCAT('+62', SUBSTRING(phone, 2)) WHERE phone LIKE '08%';
+UPDATE orders SET phone = CONCAT('+', phone) WHERE phone LIKE '628%';
+UPDATE orders SET phone = CONCAT('+62', phone) WHERE phone LIKE '8%';
+
+UPDATE quotations SET phone = REPLACE(REPLACE(REPLACE(phone, ' ', ''), '-', ''), '.', '');
+UPDATE quotations SET phone = CONCAT('+62', SUBSTRING(phone, 2)) WHERE phone LIKE '08%';
+UPDATE quotations SET phone = CONCAT('+', phone) WHERE phone LIKE '628%';
+UPDATE quotations SET phone = CONCAT('+62', phone) WHERE phone LIKE '8%';
+
+UPDATE subscriptions SET phone = REPLACE(REPLACE(REPLACE(phone, ' ', ''), '-', ''), '.', '');
+UPDATE subscriptions SET phone = CONCAT('+62', SUBSTRING(phone, 2)) WHERE phone LIKE '08%';
+UPDATE subscriptions SET phone = CONCAT('+', phone) WHERE phone LIKE '628%';
+UPDATE subscriptions SET phone = CONCAT('+62', phone) WHERE phone LIKE '8%';
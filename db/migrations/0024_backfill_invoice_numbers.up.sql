@@ -0,0 +1,23 @@
+-- Number the orders placed before invoice numbers existed, in the order they
+-- were created within each month. Months are taken in WIB (UTC+7), like the
+-- numbers handed out by the application, and continue after any number
+-- already used that month.
+UPDATE orders o
+JOIN (
+    SELECT id,
+        DATE_FORMAT(DATE_ADD(created_at, INTERVAL 7 HOUR), '%Y-%m') AS period,
+        ROW_NUMBER() OVER (PARTITION BY DATE_FORMAT(DATE_ADD(created_at, INTERVAL 7 HOUR), '%Y-%m') ORDER BY created_at, id) AS seq
+    FROM orders
+    WHERE invoice_number IS NULL
+) numbered ON numbered.id = o.id
+LEFT JOIN invoice_sequences s ON s.period = numbered.period
+SET o.invoice_number = CONCAT('INV/', REPLACE(numbered.period, '-', '/'), '/', LPAD(COALESCE(s.last_number, 0) + numbered.seq, 4, '0'));
+
+INSERT INTO invoice_sequences(period, last_number)
+SELECT period, last_number FROM (
+    SELECT REPLACE(SUBSTRING(invoice_number, 5, 7), '/', '-') AS period, MAX(CAST(SUBSTRING(invoice_number, 13) AS UNSIGNED)) AS last_number
+    FROM orders
+    WHERE invoice_number IS NOT NULL
+    GROUP BY period
+) numbers
+ON DUPLICATE KEY UPDATE last_number = GREATEST(invoice_sequences.last_number, numbers.last_number);
@@ -0,0 +1,5 @@
+-- Give the orders placed before tracking links existed a token in the same
+-- form as helper.NewTrackingToken: 32 random bytes, unpadded base64url.
+UPDATE orders
+SET tracking_token = REPLACE(REPLACE(TRIM(TRAILING '=' FROM TO_BASE64(RANDOM_BYTES(32))), '+', '-'), '/', '_')
+WHERE tracking_token IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX idx_payment_links_status ON payment_links;
+
+ALTER TABLE payment_links DROP COLUMN paid_amount;
@@ -0,0 +1,3 @@
+ALTER TABLE payment_links ADD COLUMN paid_amount DOUBLE NOT NULL DEFAULT 0 AFTER amount;
+
+CREATE INDEX idx_payment_links_status ON payment_links(status);
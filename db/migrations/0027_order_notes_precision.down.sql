@@ -0,0 +1 @@
+ALTER TABLE order_notes MODIFY created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
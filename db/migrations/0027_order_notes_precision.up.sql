@@ -0,0 +1 @@
+ALTER TABLE order_notes MODIFY created_at TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6);
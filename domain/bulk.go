@@ -0,0 +1,20 @@
+package domain
+
+const (
+	BulkActionStatus  = "status"
+	BulkActionCourier = "courier"
+	BulkActionCancel  = "cancel"
+)
+
+type BulkOrderResult struct {
+	Id      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkOrderReport struct {
+	Processed int                `json:"processed"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []*BulkOrderResult `json:"results"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "strings"
+
+// Order codes look like KH-7Q3F9: short enough to read over the phone, with
+// the letters and digits that are easy to confuse (0/O, 1/I) left out.
+const (
+	OrderCodePrefix   = "KH-"
+	OrderCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+	OrderCodeLength   = 5
+)
+
+// ParseOrderCode normalises what staff type in, with or without the prefix
+// and in any case. It reports false when the input cannot be an order code.
+func ParseOrderCode(value string) (string, bool) {
+	code := strings.ToUpper(strings.TrimSpace(value))
+	code = strings.TrimPrefix(code, OrderCodePrefix)
+	if len(code) != OrderCodeLength {
+		return "", false
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(OrderCodeAlphabet, r) {
+			return "", false
+		}
+	}
+	return OrderCodePrefix + code, true
+}
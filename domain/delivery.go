@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// ManifestStatuses are the order statuses that still have to be delivered.
+var ManifestStatuses = []string{OrderStatusConfirmed, OrderStatusCooking, OrderStatusDelivering}
+
+type DeliveryManifest struct {
+	Date           Date            `json:"date"`
+	TotalOrders    int             `json:"total_orders"`
+	TotalToCollect float64         `json:"total_to_collect"`
+	Areas          []*ManifestArea `json:"areas"`
+}
+
+type ManifestArea struct {
+	Kecamatan      string          `json:"kecamatan"`
+	Desa           string          `json:"desa"`
+	TotalToCollect float64         `json:"total_to_collect"`
+	Stops          []*ManifestStop `json:"stops"`
+}
+
+type ManifestStop struct {
+	OrderId         string   `json:"order_id"`
+	DeliverySlot    string   `json:"delivery_slot"`
+	Name            string   `json:"name"`
+	Phone           string   `json:"phone"`
+	Alamat          string   `json:"alamat"`
+	ProductName     string   `json:"product_name"`
+	Variant         string   `json:"variant"`
+	Quantity        int      `json:"quantity"`
+	AmountToCollect float64  `json:"amount_to_collect"`
+	CustomerNote    string   `json:"customer_note"`
+	Notes           []string `json:"notes"`
+}
+
+type DeliveryZone struct {
+	Id          string     `json:"id"`
+	Kecamatan   string     `json:"kecamatan" validate:"required,max=100"`
+	Desa        string     `json:"desa" validate:"max=100"`
+	Fee         int        `json:"fee" validate:"gte=0"`
+	MinOrder    int        `json:"min_order" validate:"gte=0"`
+	Deliverable bool       `json:"deliverable"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ModifiedAt  *time.Time `json:"modified_at"`
+}
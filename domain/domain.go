@@ -8,24 +8,82 @@ type Domain struct {
 	Description   string     `json:"description" validate:"alphanum"`
 	Stock         int        `json:"stock" validate:"required,number"`
 	Price         int        `json:"price" validate:"required,number"`
+	Category      string     `json:"category" validate:"max=50"`
 	ImageMetadata string     `json:"image_metadata" validate:"max=255"`
 	CreatedAt     *time.Time `json:"created_at" validate:"required"`
 	ModifiedAt    *time.Time `json:"modified_at" validate:"required"`
 }
 
 type Orders struct {
-	Id          string     `json:"id"`
-	ProductId   string     `json:"product_id"`
-	ProductName string     `json:"product_name"`
-	Name        string     `json:"name"`
-	Phone       string     `json:"phone"`
-	Alamat      string     `json:"alamat"`
-	Kecamatan   string     `json:"kecamatan"`
-	Desa        string     `json:"desa"`
-	Username    string     `json:"username"`
-	Quantity    int        `json:"quantity"`
-	Total       float64    `json:"total" validate:"required"`
-	Status      string     `json:"status"`
-	CreatedAt   *time.Time `json:"created_at"`
-	ModifiedAt  *time.Time `json:"modified_at"`
+	Id            string     `json:"id"`
+	Code          string     `json:"code"`
+	InvoiceNumber string     `json:"invoice_number"`
+	TrackingToken string     `json:"-"`
+	ProductId     string     `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	Variant       string     `json:"variant" validate:"max=50"`
+	Name          string     `json:"name"`
+	Phone         string     `json:"phone"`
+	Alamat        string     `json:"alamat"`
+	Kecamatan     string     `json:"kecamatan"`
+	KecamatanCode string     `json:"kecamatan_code"`
+	Desa          string     `json:"desa"`
+	DesaCode      string     `json:"desa_code"`
+	Username      string     `json:"username"`
+	Quantity      int        `json:"quantity"`
+	Subtotal      float64    `json:"subtotal"`
+	Discount      float64    `json:"discount"`
+	VoucherCode   string     `json:"voucher_code" validate:"max=30"`
+	DeliveryFee   float64    `json:"delivery_fee"`
+	Total         float64    `json:"total" validate:"required"`
+	AmountPaid    float64    `json:"amount_paid"`
+	PaymentState  string     `json:"payment_state"`
+	PaymentMethod string     `json:"payment_method"`
+	ReservedUntil *time.Time `json:"reserved_until"`
+	Status        string     `json:"status"`
+	DeliveryDate  *Date      `json:"delivery_date"`
+	DeliverySlot  string     `json:"delivery_slot" validate:"max=20"`
+	Courier       string     `json:"courier"`
+	CustomerNote  string     `json:"customer_note" validate:"max=500"`
+	CreatedAt     *time.Time `json:"created_at"`
+	ModifiedAt    *time.Time `json:"modified_at"`
+
+	Product  *Domain         `json:"product,omitempty"`
+	Customer *Users          `json:"customer,omitempty"`
+	Payments []*Payment      `json:"payments,omitempty"`
+	History  []*OrderHistory `json:"history,omitempty"`
+	Notes    []*OrderNote    `json:"notes,omitempty"`
+}
+
+// OrderExpand selects the relations loaded together with an order.
+type OrderExpand struct {
+	Product  bool
+	Customer bool
+	Payments bool
+	History  bool
+	Notes    bool
 }
+
+const (
+	DefaultCategory = "umum"
+
+	OrderStatusPending    = "pending"
+	OrderStatusVerifying  = "verifying"
+	OrderStatusConfirmed  = "confirmed"
+	OrderStatusCooking    = "cooking"
+	OrderStatusDelivering = "delivering"
+	OrderStatusDelivered  = "delivered"
+	OrderStatusCancelled  = "cancelled"
+
+	// Refunded statuses are set when a refund on a cancelled order is
+	// approved, never by hand.
+	OrderStatusPartiallyRefunded = "partially_refunded"
+	OrderStatusRefunded          = "refunded"
+)
+
+var OrderStatuses = []string{OrderStatusPending, OrderStatusVerifying, OrderStatusConfirmed, OrderStatusCooking,
+	OrderStatusDelivering, OrderStatusDelivered, OrderStatusCancelled}
+
+// CancelledOrderStatuses are the statuses of orders that will not be made or
+// delivered any more.
+var CancelledOrderStatuses = []string{OrderStatusCancelled, OrderStatusPartiallyRefunded, OrderStatusRefunded}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	FeedbackStatusPending  = "pending"
+	FeedbackStatusApproved = "approved"
+	FeedbackStatusRejected = "rejected"
+)
+
+// Feedback is left by the customer once an order is delivered: a rating for
+// the order as a whole and one for the product. Only approved feedback counts
+// towards a product's average rating.
+type Feedback struct {
+	Id            string     `json:"id"`
+	OrderId       string     `json:"order_id"`
+	ProductId     string     `json:"product_id"`
+	Username      string     `json:"username"`
+	Rating        int        `json:"rating"`
+	ProductRating int        `json:"product_rating"`
+	Comment       string     `json:"comment"`
+	Status        string     `json:"status"`
+	ModeratedBy   string     `json:"moderated_by"`
+	ModeratedAt   *time.Time `json:"moderated_at"`
+	CreatedAt     *time.Time `json:"created_at"`
+}
+
+type ProductRating struct {
+	ProductId     string  `json:"product_id"`
+	AverageRating float64 `json:"average_rating"`
+	RatingCount   int     `json:"rating_count"`
+}
+
+// RatedProduct is a product as listed to admins, with its approved ratings.
+// Domain is embedded so the product fields stay at the top level of the JSON
+// and the rating fields are only added next to them.
+type RatedProduct struct {
+	*Domain
+	AverageRating float64 `json:"average_rating"`
+	RatingCount   int     `json:"rating_count"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+const (
+	OrderHistoryStatus         = "status"
+	OrderHistoryEdit           = "edit"
+	OrderHistoryVoucherRemoved = "voucher_removed"
+)
+
+type OrderChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type OrderHistory struct {
+	Id        int64         `json:"id"`
+	OrderId   string        `json:"order_id"`
+	Action    string        `json:"action"`
+	Changes   []OrderChange `json:"changes"`
+	ChangedBy string        `json:"changed_by"`
+	CreatedAt *time.Time    `json:"created_at"`
+}
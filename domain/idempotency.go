@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// IdempotencyKey remembers the outcome of a request sent with an
+// Idempotency-Key header so a retry gets the same answer instead of a
+// duplicate order.
+type IdempotencyKey struct {
+	Key            string    `json:"key"`
+	Fingerprint    string    `json:"fingerprint"`
+	OrderId        string    `json:"order_id"`
+	ResponseStatus int       `json:"response_status"`
+	Replayed       bool      `json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
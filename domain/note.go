@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// OrderNote is an internal note on an order. Replies point at the note they
+// answer through ParentId.
+type OrderNote struct {
+	Id        string       `json:"id"`
+	OrderId   string       `json:"order_id"`
+	ParentId  string       `json:"parent_id"`
+	Author    string       `json:"author"`
+	Body      string       `json:"body"`
+	CreatedAt *time.Time   `json:"created_at"`
+	Replies   []*OrderNote `json:"replies,omitempty"`
+}
+
+// ThreadNotes nests replies under their parent. Notes must be in creation
+// order; the result keeps that order on every level. Parents are indexed
+// before any reply is attached, so a reply sorted ahead of its parent still
+// lands in the right thread.
+func ThreadNotes(notes []*OrderNote) []*OrderNote {
+	byId := make(map[string]*OrderNote, len(notes))
+	for _, note := range notes {
+		byId[note.Id] = note
+	}
+	threads := []*OrderNote{}
+	for _, note := range notes {
+		if parent, ok := byId[note.ParentId]; ok && parent != note {
+			parent.Replies = append(parent.Replies, note)
+			continue
+		}
+		threads = append(threads, note)
+	}
+	return threads
+}
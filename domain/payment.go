@@ -0,0 +1,105 @@
+package domain
+
+import "time"
+
+const (
+	PaymentMethodCash     = "cash"
+	PaymentMethodTransfer = "transfer"
+	PaymentMethodQris     = "qris"
+	PaymentMethodGateway  = "gateway"
+	// PaymentMethodCreditNote pays with the balance of a credit note; the
+	// payment reference holds the credit note id.
+	PaymentMethodCreditNote = "credit_note"
+
+	PaymentStateUnpaid   = "unpaid"
+	PaymentStatePartial  = "partial"
+	PaymentStatePaid     = "paid"
+	PaymentStateOverpaid = "overpaid"
+
+	ProofStatusPending  = "pending"
+	ProofStatusApproved = "approved"
+	ProofStatusRejected = "rejected"
+)
+
+type Payment struct {
+	Id         string     `json:"id"`
+	OrderId    string     `json:"order_id"`
+	Method     string     `json:"method" validate:"required,oneof=cash transfer qris"`
+	Amount     float64    `json:"amount" validate:"required,gt=0"`
+	Reference  string     `json:"reference" validate:"max=100"`
+	Proof      string     `json:"proof" validate:"max=255"`
+	ReceivedBy string     `json:"received_by"`
+	PaidAt     *time.Time `json:"paid_at"`
+	VoidedAt   *time.Time `json:"voided_at"`
+	VoidedBy   string     `json:"voided_by"`
+	VoidReason string     `json:"void_reason"`
+	CreatedAt  *time.Time `json:"created_at"`
+}
+
+type PaymentProof struct {
+	Id           string     `json:"id"`
+	OrderId      string     `json:"order_id"`
+	Amount       float64    `json:"amount" validate:"required,gt=0"`
+	BankName     string     `json:"bank_name" validate:"max=50"`
+	AccountName  string     `json:"account_name" validate:"max=100"`
+	Image        string     `json:"image"`
+	Status       string     `json:"status"`
+	UploadedBy   string     `json:"uploaded_by"`
+	ReviewedBy   string     `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	RejectReason string     `json:"reject_reason"`
+	PaymentId    string     `json:"payment_id"`
+	CreatedAt    *time.Time `json:"created_at"`
+}
+
+// PaymentLink is a hosted payment page created for the outstanding amount of
+// an order. PaidAmount is what the provider reported as collected, which can
+// differ from Amount for a link held for review.
+type PaymentLink struct {
+	Id              string     `json:"id"`
+	OrderId         string     `json:"order_id"`
+	Provider        string     `json:"provider"`
+	ProviderOrderId string     `json:"provider_order_id"`
+	Amount          float64    `json:"amount"`
+	PaidAmount      float64    `json:"paid_amount"`
+	Token           string     `json:"token"`
+	RedirectURL     string     `json:"redirect_url"`
+	Status          string     `json:"status"`
+	TransactionId   string     `json:"transaction_id"`
+	PaymentId       string     `json:"payment_id"`
+	CreatedAt       *time.Time `json:"created_at"`
+	ModifiedAt      *time.Time `json:"modified_at"`
+}
+
+type PaymentSummary struct {
+	OrderId     string     `json:"order_id"`
+	Total       float64    `json:"total"`
+	Paid        float64    `json:"paid"`
+	Refunded    float64    `json:"refunded"`
+	Outstanding float64    `json:"outstanding"`
+	State       string     `json:"state"`
+	Payments    []*Payment `json:"payments"`
+}
+
+// PaymentStateOf derives how far an order has been paid. A down payment
+// shows up as partial until the balance is settled.
+func PaymentStateOf(total float64, paid float64) string {
+	switch {
+	case paid <= 0:
+		return PaymentStateUnpaid
+	case paid < total:
+		return PaymentStatePartial
+	case paid == total:
+		return PaymentStatePaid
+	default:
+		return PaymentStateOverpaid
+	}
+}
+
+type Invoice struct {
+	Number        string          `json:"number"`
+	IssuedAt      time.Time       `json:"issued_at"`
+	Order         *Orders         `json:"order"`
+	Summary       *PaymentSummary `json:"summary"`
+	TrackingToken string          `json:"tracking_token"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+const CapacityOverall = "all"
+
+// ProductionStatuses are the order statuses the kitchen has to cook for.
+var ProductionStatuses = []string{OrderStatusConfirmed, OrderStatusCooking}
+
+type DailyCapacity struct {
+	Category    string `json:"category" validate:"required,max=50"`
+	MaxPortions int    `json:"max_portions" validate:"gte=0"`
+}
+
+type ProductionSettings struct {
+	CutoffDays int    `json:"cutoff_days" validate:"gte=0"`
+	CutoffTime string `json:"cutoff_time" validate:"required,datetime=15:04"`
+}
+
+type BlackoutDate struct {
+	Date   Date   `json:"date"`
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type ProductionSheet struct {
+	Date          Date              `json:"date"`
+	TotalPortions int               `json:"total_portions"`
+	TotalOrders   int               `json:"total_orders"`
+	Items         []*ProductionItem `json:"items"`
+}
+
+type ProductionItem struct {
+	ProductId   string             `json:"product_id"`
+	ProductName string             `json:"product_name"`
+	Category    string             `json:"category"`
+	Variant     string             `json:"variant"`
+	Quantity    int                `json:"quantity"`
+	Orders      []*ProductionOrder `json:"orders"`
+}
+
+type ProductionOrder struct {
+	OrderId       string   `json:"order_id"`
+	Code          string   `json:"code"`
+	InvoiceNumber string   `json:"invoice_number"`
+	Name          string   `json:"name"`
+	Quantity      int      `json:"quantity"`
+	Status        string   `json:"status"`
+	CustomerNote  string   `json:"customer_note"`
+	Notes         []string `json:"notes"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+const (
+	QuotationStatusDraft     = "draft"
+	QuotationStatusSent      = "sent"
+	QuotationStatusAccepted  = "accepted"
+	QuotationStatusRejected  = "rejected"
+	QuotationStatusConverted = "converted"
+)
+
+type Quotation struct {
+	Id           string           `json:"id"`
+	Revision     int              `json:"revision"`
+	Status       string           `json:"status"`
+	EventName    string           `json:"event_name" validate:"max=100"`
+	Name         string           `json:"name" validate:"required,max=100"`
+	Phone        string           `json:"phone" validate:"required,max=20"`
+	Username     string           `json:"username" validate:"required"`
+	Alamat       string           `json:"alamat" validate:"required,max=255"`
+	Kecamatan    string           `json:"kecamatan" validate:"required"`
+	Desa         string           `json:"desa" validate:"required"`
+	DeliveryDate *Date            `json:"delivery_date" validate:"required"`
+	DeliverySlot string           `json:"delivery_slot" validate:"max=20"`
+	DeliveryFee  float64          `json:"delivery_fee" validate:"gte=0"`
+	ValidUntil   *Date            `json:"valid_until" validate:"required"`
+	Notes        string           `json:"notes"`
+	Items        []*QuotationItem `json:"items" validate:"required,min=1,dive"`
+	Subtotal     float64          `json:"subtotal"`
+	Total        float64          `json:"total"`
+	CreatedBy    string           `json:"created_by"`
+	CreatedAt    *time.Time       `json:"created_at"`
+	ModifiedAt   *time.Time       `json:"modified_at"`
+}
+
+type QuotationItem struct {
+	Id          int     `json:"id"`
+	ProductId   string  `json:"product_id" validate:"required"`
+	ProductName string  `json:"product_name"`
+	Variant     string  `json:"variant" validate:"max=50"`
+	Quantity    int     `json:"quantity" validate:"required,gt=0"`
+	UnitPrice   float64 `json:"unit_price" validate:"gte=0"`
+	Subtotal    float64 `json:"subtotal"`
+	OrderId     string  `json:"order_id,omitempty"`
+}
+
+// Price fills in the line subtotals and quotation totals from the items.
+func (q *Quotation) Price() {
+	q.Subtotal = 0
+	for _, item := range q.Items {
+		item.Subtotal = item.UnitPrice * float64(item.Quantity)
+		q.Subtotal += item.Subtotal
+	}
+	q.Total = q.Subtotal + q.DeliveryFee
+}
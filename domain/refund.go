@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+const (
+	RefundStatusPending  = "pending"
+	RefundStatusApproved = "approved"
+	RefundStatusRejected = "rejected"
+
+	RefundMethodCash       = "cash"
+	RefundMethodTransfer   = "transfer"
+	RefundMethodCreditNote = "credit_note"
+)
+
+// Refund gives back part or all of one payment. It takes effect only once
+// approved; a refund by credit note then issues the credit note.
+type Refund struct {
+	Id           string     `json:"id"`
+	OrderId      string     `json:"order_id"`
+	PaymentId    string     `json:"payment_id"`
+	Amount       float64    `json:"amount" validate:"required,gt=0"`
+	Method       string     `json:"method" validate:"required,oneof=cash transfer credit_note"`
+	Reason       string     `json:"reason" validate:"required,max=255"`
+	Status       string     `json:"status"`
+	RequestedBy  string     `json:"requested_by"`
+	ReviewedBy   string     `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	RejectReason string     `json:"reject_reason"`
+	CreditNoteId string     `json:"credit_note_id"`
+	CreatedAt    *time.Time `json:"created_at"`
+}
+
+// CreditNote is money owed to a customer that is spent on their later orders
+// as a credit_note payment.
+type CreditNote struct {
+	Id        string     `json:"id"`
+	Username  string     `json:"username"`
+	OrderId   string     `json:"order_id"`
+	Amount    float64    `json:"amount"`
+	Balance   float64    `json:"balance"`
+	IssuedBy  string     `json:"issued_by"`
+	CreatedAt *time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+const (
+	RegionLevelProvinsi  = "provinsi"
+	RegionLevelKabupaten = "kabupaten"
+	RegionLevelKecamatan = "kecamatan"
+	RegionLevelDesa      = "desa"
+)
+
+type Region struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Level      string `json:"level"`
+	ParentCode string `json:"parent_code,omitempty"`
+}
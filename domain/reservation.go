@@ -0,0 +1,9 @@
+package domain
+
+// ReservationPolicy sets how long stock stays reserved for an unpaid order
+// paid with the given method. A TTL of 0 keeps the reservation until the
+// order is handled by hand.
+type ReservationPolicy struct {
+	PaymentMethod string `json:"payment_method" validate:"omitempty,oneof=cash transfer qris gateway"`
+	TTLMinutes    int    `json:"ttl_minutes" validate:"gte=0"`
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+const (
+	OrderSortCreatedAt    = "created_at"
+	OrderSortDeliveryDate = "delivery_date"
+	OrderSortTotal        = "total"
+)
+
+// OrderFilter narrows an order search. Zero values mean "any".
+type OrderFilter struct {
+	Statuses     []string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	DeliveryFrom *Date
+	DeliveryTo   *Date
+	Kecamatan    string
+	Desa         string
+	Username     string
+	Phone        string
+	ProductId    string
+	MinTotal     *float64
+	MaxTotal     *float64
+	Query        string
+	SortBy       string
+	Descending   bool
+	Limit        int
+	After        *OrderCursor
+}
+
+// Narrows reports whether the filter selects a subset of orders. Sorting,
+// paging and the page size alone still match every order.
+func (f *OrderFilter) Narrows() bool {
+	return len(f.Statuses) > 0 || f.CreatedFrom != nil || f.CreatedTo != nil || f.DeliveryFrom != nil || f.DeliveryTo != nil ||
+		f.Kecamatan != "" || f.Desa != "" || f.Username != "" || f.Phone != "" || f.ProductId != "" ||
+		f.MinTotal != nil || f.MaxTotal != nil || f.Query != ""
+}
+
+// OrderCursor points at the last order of a page: the value of the sort
+// column and the id that breaks ties.
+type OrderCursor struct {
+	Value interface{}
+	Id    string
+}
+
+type OrderPage struct {
+	Orders     []*Orders
+	Total      int
+	NextCursor string
+}
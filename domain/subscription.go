@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusPaused    = "paused"
+	SubscriptionStatusCancelled = "cancelled"
+)
+
+type Subscription struct {
+	Id           string             `json:"id"`
+	Username     string             `json:"username" validate:"required"`
+	Name         string             `json:"name" validate:"required,max=100"`
+	Phone        string             `json:"phone" validate:"required,max=20"`
+	Alamat       string             `json:"alamat" validate:"required,max=255"`
+	Kecamatan    string             `json:"kecamatan" validate:"required"`
+	Desa         string             `json:"desa" validate:"required"`
+	ProductId    string             `json:"product_id" validate:"required"`
+	Variant      string             `json:"variant" validate:"max=50"`
+	Quantity     int                `json:"quantity" validate:"required,gt=0"`
+	DeliverySlot string             `json:"delivery_slot" validate:"max=20"`
+	DaysOfWeek   []int              `json:"days_of_week" validate:"required,min=1,max=7,dive,min=1,max=7"`
+	StartDate    *Date              `json:"start_date" validate:"required"`
+	EndDate      *Date              `json:"end_date"`
+	Status       string             `json:"status"`
+	PausedUntil  *Date              `json:"paused_until"`
+	SkipDates    []Date             `json:"skip_dates"`
+	Runs         []*SubscriptionRun `json:"runs,omitempty"`
+	CreatedAt    *time.Time         `json:"created_at"`
+	ModifiedAt   *time.Time         `json:"modified_at"`
+}
+
+type SubscriptionRun struct {
+	DeliveryDate Date       `json:"delivery_date"`
+	OrderId      string     `json:"order_id"`
+	Error        string     `json:"error"`
+	ModifiedAt   *time.Time `json:"modified_at"`
+}
+
+// IsoWeekday numbers days Monday=1 to Sunday=7, the way days_of_week is
+// stored.
+func IsoWeekday(date Date) int {
+	if date.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(date.Weekday())
+}
+
+// DeliversOn reports whether the subscription should produce an order for
+// date, taking the schedule, pauses and skipped dates into account.
+func (s *Subscription) DeliversOn(date Date) bool {
+	if date.Before(s.StartDate.Time) || s.EndDate != nil && date.After(s.EndDate.Time) {
+		return false
+	}
+	switch s.Status {
+	case SubscriptionStatusActive:
+	case SubscriptionStatusPaused:
+		if s.PausedUntil == nil || !date.After(s.PausedUntil.Time) {
+			return false
+		}
+	default:
+		return false
+	}
+	if !slices.Contains(s.DaysOfWeek, IsoWeekday(date)) {
+		return false
+	}
+	for _, skip := range s.SkipDates {
+		if skip.Equal(date.Time) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// OrderTracking is what a customer sees through a tracking link. It leaves
+// out the name, phone and address so a leaked link only shows progress.
+type OrderTracking struct {
+	Code          string           `json:"code"`
+	InvoiceNumber string           `json:"invoice_number"`
+	ProductName   string           `json:"product_name"`
+	Variant       string           `json:"variant"`
+	Quantity      int              `json:"quantity"`
+	Status        string           `json:"status"`
+	DeliveryDate  *Date            `json:"delivery_date"`
+	DeliverySlot  string           `json:"delivery_slot"`
+	Total         float64          `json:"total"`
+	Paid          float64          `json:"paid"`
+	Outstanding   float64          `json:"outstanding"`
+	PaymentState  string           `json:"payment_state"`
+	Timeline      []*TrackingEvent `json:"timeline"`
+}
+
+type TrackingEvent struct {
+	Status string     `json:"status"`
+	At     *time.Time `json:"at"`
+}
+
+type TrackingLink struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+type Voucher struct {
+	Id               string     `json:"id"`
+	Code             string     `json:"code" validate:"required,max=30"`
+	Description      string     `json:"description" validate:"max=255"`
+	Type             string     `json:"type" validate:"required,oneof=percentage fixed"`
+	Value            float64    `json:"value" validate:"required,gt=0"`
+	MaxDiscount      float64    `json:"max_discount" validate:"gte=0"`
+	MinOrder         float64    `json:"min_order" validate:"gte=0"`
+	UsageLimit       int        `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int        `json:"per_customer_limit" validate:"gte=0"`
+	UsedCount        int        `json:"used_count"`
+	ProductIds       []string   `json:"product_ids"`
+	Categories       []string   `json:"categories"`
+	ValidFrom        *time.Time `json:"valid_from"`
+	ValidUntil       *time.Time `json:"valid_until"`
+	Active           bool       `json:"active"`
+	CreatedAt        *time.Time `json:"created_at"`
+	ModifiedAt       *time.Time `json:"modified_at"`
+}
+
+type DiscountRule struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	MinQuantity int        `json:"min_quantity" validate:"required,gt=0"`
+	Type        string     `json:"type" validate:"required,oneof=percentage fixed"`
+	Value       float64    `json:"value" validate:"required,gt=0"`
+	ProductId   string     `json:"product_id" validate:"max=6"`
+	Category    string     `json:"category" validate:"max=50"`
+	ValidFrom   *time.Time `json:"valid_from"`
+	ValidUntil  *time.Time `json:"valid_until"`
+	Active      bool       `json:"active"`
+	CreatedAt   *time.Time `json:"created_at"`
+	ModifiedAt  *time.Time `json:"modified_at"`
+}
+
+// DiscountAmount works out a percentage or fixed discount on base. A
+// positive limit caps percentage discounts; the result never exceeds base.
+func DiscountAmount(kind string, value float64, limit float64, base float64) float64 {
+	amount := value
+	if kind == DiscountPercentage {
+		amount = math.Round(base * value / 100)
+		if limit > 0 {
+			amount = min(amount, limit)
+		}
+	}
+	return max(min(amount, base), 0)
+}
+
+func ValidAt(from *time.Time, until *time.Time, at time.Time) bool {
+	if from != nil && at.Before(*from) {
+		return false
+	}
+	if until != nil && at.After(*until) {
+		return false
+	}
+	return true
+}
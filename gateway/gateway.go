@@ -0,0 +1,44 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	StatusPending = "pending"
+	StatusPaid    = "paid"
+	StatusFailed  = "failed"
+	// StatusReview is never sent by a provider. It marks a link whose paid
+	// notification could not be applied on its own and needs an admin.
+	StatusReview = "review"
+)
+
+var ErrInvalidSignature = errors.New("invalid notification signature")
+
+type LinkRequest struct {
+	OrderId string
+	Amount  float64
+}
+
+type Link struct {
+	Token       string
+	RedirectURL string
+}
+
+type Notification struct {
+	OrderId       string
+	TransactionId string
+	Status        string
+	PaymentType   string
+	Amount        float64
+}
+
+// Provider is a hosted payment page that reports back through an HTTP
+// notification. ParseNotification must reject bodies it cannot authenticate
+// with ErrInvalidSignature.
+type Provider interface {
+	Name() string
+	CreatePaymentLink(ctx context.Context, request LinkRequest) (*Link, error)
+	ParseNotification(body []byte) (*Notification, error)
+}
@@ -0,0 +1,153 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultMidtransURL = "https://app.sandbox.midtrans.com"
+
+type Midtrans struct {
+	serverKey string
+	baseURL   string
+	client    *http.Client
+}
+
+func NewMidtrans(serverKey string, baseURL string) *Midtrans {
+	if baseURL == "" {
+		baseURL = defaultMidtransURL
+	}
+	return &Midtrans{
+		serverKey: serverKey,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func NewProvider() Provider {
+	return NewMidtrans(os.Getenv("MIDTRANS_SERVER_KEY"), os.Getenv("MIDTRANS_BASE_URL"))
+}
+
+func (m *Midtrans) Name() string {
+	return "midtrans"
+}
+
+type snapRequest struct {
+	TransactionDetails struct {
+		OrderId     string `json:"order_id"`
+		GrossAmount int64  `json:"gross_amount"`
+	} `json:"transaction_details"`
+}
+
+type snapResponse struct {
+	Token         string   `json:"token"`
+	RedirectURL   string   `json:"redirect_url"`
+	ErrorMessages []string `json:"error_messages"`
+}
+
+func (m *Midtrans) CreatePaymentLink(ctx context.Context, request LinkRequest) (*Link, error) {
+	if m.serverKey == "" {
+		return nil, errors.New("midtrans server key is not configured")
+	}
+	var payload snapRequest
+	payload.TransactionDetails.OrderId = request.OrderId
+	payload.TransactionDetails.GrossAmount = int64(math.Round(request.Amount))
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/snap/v1/transactions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(m.serverKey, "")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result snapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("midtrans: unexpected response (%d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("midtrans: %d %s", resp.StatusCode, strings.Join(result.ErrorMessages, "; "))
+	}
+	return &Link{Token: result.Token, RedirectURL: result.RedirectURL}, nil
+}
+
+type midtransNotification struct {
+	OrderId           string `json:"order_id"`
+	TransactionId     string `json:"transaction_id"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
+	PaymentType       string `json:"payment_type"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+}
+
+func (m *Midtrans) ParseNotification(body []byte) (*Notification, error) {
+	var payload midtransNotification
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	if m.serverKey == "" || !m.validSignature(payload) {
+		return nil, ErrInvalidSignature
+	}
+	amount, err := strconv.ParseFloat(payload.GrossAmount, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Notification{
+		OrderId:       payload.OrderId,
+		TransactionId: payload.TransactionId,
+		Status:        midtransStatus(payload.TransactionStatus, payload.FraudStatus),
+		PaymentType:   payload.PaymentType,
+		Amount:        amount,
+	}, nil
+}
+
+// Signature returns the signature_key Midtrans puts on a notification.
+func (m *Midtrans) Signature(orderId string, statusCode string, grossAmount string) string {
+	sum := sha512.Sum512([]byte(orderId + statusCode + grossAmount + m.serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *Midtrans) validSignature(payload midtransNotification) bool {
+	expected := m.Signature(payload.OrderId, payload.StatusCode, payload.GrossAmount)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(payload.SignatureKey))) == 1
+}
+
+func midtransStatus(status string, fraud string) string {
+	switch status {
+	case "settlement":
+		return StatusPaid
+	case "capture":
+		if fraud == "" || fraud == "accept" {
+			return StatusPaid
+		}
+		return StatusPending
+	case "deny", "cancel", "expire", "failure":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
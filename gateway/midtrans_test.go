@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePaymentLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		if !ok || user != "server-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]any{"error_messages": []string{"Access denied"}})
+			return
+		}
+		var payload snapRequest
+		json.NewDecoder(r.Body).Decode(&payload)
+		if r.URL.Path != "/snap/v1/transactions" || payload.TransactionDetails.GrossAmount != 150000 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]any{"error_messages": []string{"bad request"}})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{
+			"token":        "snap-token",
+			"redirect_url": "https://pay.example/" + payload.TransactionDetails.OrderId,
+		})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		serverKey string
+		amount    float64
+		wantErr   bool
+	}{
+		{"success", "server-key", 150000, false},
+		{"rejected by provider", "wrong-key", 150000, true},
+		{"missing server key", "", 150000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewMidtrans(tt.serverKey, server.URL)
+			link, err := provider.CreatePaymentLink(context.Background(), LinkRequest{OrderId: "order-1", Amount: tt.amount})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "snap-token", link.Token)
+			assert.Equal(t, "https://pay.example/order-1", link.RedirectURL)
+		})
+	}
+}
+
+func TestParseNotification(t *testing.T) {
+	provider := NewMidtrans("server-key", "")
+	body := func(status string, signature string) []byte {
+		payload, _ := json.Marshal(map[string]string{
+			"order_id":           "order-1",
+			"transaction_id":     "trx-1",
+			"transaction_status": status,
+			"payment_type":       "qris",
+			"status_code":        "200",
+			"gross_amount":       "150000.00",
+			"signature_key":      signature,
+		})
+		return payload
+	}
+	valid := provider.Signature("order-1", "200", "150000.00")
+
+	tests := []struct {
+		name       string
+		body       []byte
+		wantStatus string
+		wantErr    error
+	}{
+		{"settlement", body("settlement", valid), StatusPaid, nil},
+		{"expired", body("expire", valid), StatusFailed, nil},
+		{"pending", body("pending", valid), StatusPending, nil},
+		{"bad signature", body("settlement", "deadbeef"), "", ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notification, err := provider.ParseNotification(tt.body)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, notification.Status)
+			assert.Equal(t, "trx-1", notification.TransactionId)
+			assert.Equal(t, 150000.0, notification.Amount)
+		})
+	}
+}
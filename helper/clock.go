@@ -0,0 +1,21 @@
+package helper
+
+import "time"
+
+var location = loadLocation()
+
+func loadLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		return time.FixedZone("WIB", 7*60*60)
+	}
+	return loc
+}
+
+func Location() *time.Location {
+	return location
+}
+
+func Now() time.Time {
+	return time.Now().In(location)
+}
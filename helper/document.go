@@ -0,0 +1,191 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+type Business struct {
+	Name    string
+	Address string
+	Phone   string
+}
+
+func BusinessFromEnv() Business {
+	business := Business{
+		Name:    os.Getenv("BUSINESS_NAME"),
+		Address: os.Getenv("BUSINESS_ADDRESS"),
+		Phone:   os.Getenv("BUSINESS_PHONE"),
+	}
+	if business.Name == "" {
+		business.Name = "Khaira Catering"
+	}
+	return business
+}
+
+type DocumentField struct {
+	Label string
+	Value string
+	Bold  bool
+}
+
+type DocumentItem struct {
+	Description string
+	Detail      string
+	Quantity    int
+	UnitPrice   float64
+}
+
+func (i DocumentItem) Amount() float64 {
+	return i.UnitPrice * float64(i.Quantity)
+}
+
+type DocumentTable struct {
+	Title  string
+	Header []string
+	Widths []float64
+	Rows   [][]string
+}
+
+// Document is a single-party commercial document such as an invoice: the
+// business letterhead, who it is addressed to, line items and a totals block.
+type Document struct {
+	Business  Business
+	Title     string
+	Meta      []DocumentField
+	Recipient []string
+	Items     []DocumentItem
+	Totals    []DocumentField
+	Tables    []DocumentTable
+	Notes     []string
+	QRCode    []byte
+	QRCaption string
+}
+
+func (d *Document) WritePDF(w io.Writer) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Halaman %d", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	top := pdf.GetY()
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(110, 7, tr(d.Business.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{d.Business.Address, d.Business.Phone} {
+		if line != "" {
+			pdf.MultiCell(110, 4.5, tr(line), "", "L", false)
+		}
+	}
+	bottom := pdf.GetY()
+
+	pdf.SetXY(125, top)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(70, 8, tr(d.Title), "", 2, "R", false, 0, "")
+	for _, field := range d.Meta {
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(30, 5, tr(field.Label), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(40, 5, tr(field.Value), "", 2, "R", false, 0, "")
+		pdf.SetX(125)
+	}
+	pdf.SetY(max(bottom, pdf.GetY()) + 6)
+
+	if len(d.Recipient) > 0 {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(0, 5, "Kepada:", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		for _, line := range d.Recipient {
+			if line != "" {
+				pdf.MultiCell(110, 4.5, tr(line), "", "L", false)
+			}
+		}
+		pdf.Ln(4)
+	}
+
+	widths := []float64{85, 20, 37.5, 37.5}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for i, title := range []string{"Keterangan", "Jumlah", "Harga", "Subtotal"} {
+		align := "R"
+		if i == 0 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, title, "1", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 9)
+	for _, item := range d.Items {
+		description := item.Description
+		if item.Detail != "" {
+			description += " (" + item.Detail + ")"
+		}
+		pdf.CellFormat(widths[0], 6, FitText(pdf, tr(description), widths[0]-2), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, strconv.Itoa(item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 6, FormatRupiah(item.UnitPrice), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, FormatRupiah(item.Amount()), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+	for _, field := range d.Totals {
+		style := ""
+		if field.Bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 6, tr(field.Label), "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, tr(field.Value), "", 1, "R", false, 0, "")
+	}
+
+	for _, table := range d.Tables {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(0, 6, tr(table.Title), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 9)
+		for i, title := range table.Header {
+			pdf.CellFormat(table.Widths[i], 6, tr(title), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+		for _, row := range table.Rows {
+			for i, cell := range row {
+				pdf.CellFormat(table.Widths[i], 6, FitText(pdf, tr(cell), table.Widths[i]-2), "1", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	if len(d.Notes) > 0 {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "", 8)
+		for _, note := range d.Notes {
+			pdf.MultiCell(0, 4, tr(note), "", "L", false)
+		}
+	}
+
+	if len(d.QRCode) > 0 {
+		pdf.Ln(4)
+		pdf.RegisterImageOptionsReader("qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(d.QRCode))
+		x, y := pdf.GetXY()
+		pdf.ImageOptions("qr", x, y, 30, 30, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+		pdf.SetXY(x+33, y+11)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.MultiCell(0, 4, tr(d.QRCaption), "", "L", false)
+		pdf.SetY(y + 30)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
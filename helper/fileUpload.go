@@ -1,9 +1,12 @@
 package helper
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"time"
@@ -64,3 +67,62 @@ func MoveFile(srcPath, dstPath string) error {
 
 	return nil
 }
+
+// MaxProofSize is the largest transfer proof accepted, kept under the 4 MB
+// request body limit of the server.
+const MaxProofSize = 3 << 20
+
+var (
+	ErrFileTooLarge    = errors.New("file is too large")
+	ErrUnsupportedFile = errors.New("file type is not allowed")
+)
+
+// proofExtensions maps the accepted transfer proof types to the extension the
+// stored file gets.
+var proofExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// SaveProofFile stores a transfer proof in dstDir under a new name. The type
+// is sniffed from the content rather than taken from the file name or the
+// header the client sent, and only JPEG, PNG and PDF are accepted.
+func SaveProofFile(file *multipart.FileHeader, dstDir string) (string, error) {
+	if file.Size > MaxProofSize {
+		return "", ErrFileTooLarge
+	}
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+	ext, ok := proofExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return "", ErrUnsupportedFile
+	}
+
+	filename := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
+	dstPath := filepath.Join(dstDir, filename)
+	out, err := os.Create(dstPath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	written, err := io.Copy(out, io.LimitReader(io.MultiReader(bytes.NewReader(head[:n]), src), MaxProofSize+1))
+	if err == nil && written > MaxProofSize {
+		err = ErrFileTooLarge
+	}
+	if err != nil {
+		os.Remove(dstPath)
+		return "", err
+	}
+	return filename, nil
+}
@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"bytes"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// formFile builds the multipart header a handler gets for an uploaded file.
+func formFile(t *testing.T, name string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", name)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(MaxProofSize * 2)
+	assert.NoError(t, err)
+	return form.File["image"][0]
+}
+
+func TestSaveProofFile(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	pdf := []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n")
+
+	tests := []struct {
+		name        string
+		filename    string
+		content     []byte
+		expectedExt string
+		expectedErr error
+	}{
+		{name: "png", filename: "slip.png", content: png, expectedExt: ".png"},
+		{name: "pdf under another name", filename: "slip.jpg", content: pdf, expectedExt: ".pdf"},
+		{name: "html posing as an image", filename: "slip.png", content: []byte("<html><script>alert(1)</script></html>"), expectedErr: ErrUnsupportedFile},
+		{name: "too large", filename: "slip.png", content: append(png, make([]byte, MaxProofSize)...), expectedErr: ErrFileTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filename, err := SaveProofFile(formFile(t, tt.filename, tt.content), dir)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				entries, _ := os.ReadDir(dir)
+				assert.Empty(t, entries)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedExt, filepath.Ext(filename))
+			saved, err := os.ReadFile(filepath.Join(dir, filename))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.content, saved)
+		})
+	}
+}
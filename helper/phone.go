@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// mobilePrefixes are the Indonesian mobile operator prefixes as dialled after
+// +62: Telkomsel, Indosat, XL, Axis, Smartfren and Tri.
+var mobilePrefixes = []string{
+	"811", "812", "813", "821", "822", "823", "851", "852", "853",
+	"814", "815", "816", "855", "856", "857", "858",
+	"817", "818", "819", "859", "877", "878",
+	"831", "832", "833", "838",
+	"881", "882", "883", "884", "885", "886", "887", "888", "889",
+	"895", "896", "897", "898", "899",
+}
+
+// NormalizePhone turns an Indonesian mobile number written as 08…, 62…, +62…
+// or 8…, with or without spaces and dashes, into E.164 (+628…).
+func NormalizePhone(raw string) (string, error) {
+	number := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -.()", r) {
+			return -1
+		}
+		return r
+	}, raw)
+	switch {
+	case strings.HasPrefix(number, "+62"):
+		number = number[3:]
+	case strings.HasPrefix(number, "62"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		number = number[1:]
+	}
+	if number == "" || strings.IndexFunc(number, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", errors.New("nomor telepon tidak valid")
+	}
+	if len(number) < 9 || len(number) > 12 {
+		return "", errors.New("panjang nomor telepon tidak valid")
+	}
+	if !slices.Contains(mobilePrefixes, number[:3]) {
+		return "", errors.New("nomor telepon bukan nomor seluler Indonesia")
+	}
+	return "+62" + number, nil
+}
+
+// PhoneSearchTerm reduces a full or partial phone number to the digits after
+// the country code or leading 0, so it matches stored E.164 numbers whatever
+// format it was typed in. Input without digits is returned as is.
+func PhoneSearchTerm(raw string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r < '0' || r > '9' {
+			return -1
+		}
+		return r
+	}, raw)
+	if digits == "" {
+		return strings.TrimSpace(raw)
+	}
+	switch {
+	case strings.HasPrefix(digits, "62"):
+		return digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		return digits[1:]
+	}
+	return digits
+}
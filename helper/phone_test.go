@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "local format", raw: "081234567890", expected: "+6281234567890"},
+		{name: "country code", raw: "6281234567890", expected: "+6281234567890"},
+		{name: "e164 with spaces", raw: "+62 812-3456-7890", expected: "+6281234567890"},
+		{name: "without leading zero", raw: "8951234567", expected: "+628951234567"},
+		{name: "longest number", raw: "0812345678901", expected: "+62812345678901"},
+		{name: "landline", raw: "0218765432", expectedErr: true},
+		{name: "unknown operator", raw: "0800123456", expectedErr: true},
+		{name: "too short", raw: "0812345", expectedErr: true},
+		{name: "too long", raw: "08123456789012", expectedErr: true},
+		{name: "letters", raw: "0812abc4567", expectedErr: true},
+		{name: "other country", raw: "+6012345678", expectedErr: true},
+		{name: "empty", raw: "", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizePhone(tt.raw)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestPhoneSearchTerm(t *testing.T) {
+	for raw, expected := range map[string]string{
+		"0812-3456":      "8123456",
+		"+62 812 3456":   "8123456",
+		"628123456":      "8123456",
+		"3456":           "3456",
+		"  not a phone ": "not a phone",
+	} {
+		assert.Equal(t, expected, PhoneSearchTerm(raw), raw)
+	}
+}
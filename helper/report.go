@@ -0,0 +1,112 @@
+package helper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+type Report struct {
+	Title    string
+	Subtitle string
+	Header   []string
+	Widths   []float64
+	Rows     []ReportRow
+}
+
+type ReportRow struct {
+	Cells []string
+	Bold  bool
+}
+
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(r.Header); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		if err := writer.Write(row.Cells); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (r *Report) WritePDF(w io.Writer) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetHeaderFunc(func() {
+		if pdf.PageNo() > 1 {
+			r.writeTableHeader(pdf, tr)
+		}
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, tr(r.Title), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, fmt.Sprintf("Halaman %d", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, tr(r.Title), "", 1, "L", false, 0, "")
+	if r.Subtitle != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(r.Subtitle), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	r.writeTableHeader(pdf, tr)
+	for _, row := range r.Rows {
+		style := ""
+		if row.Bold {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 9)
+		for i, cell := range row.Cells {
+			width := r.width(i)
+			pdf.CellFormat(width, 6, FitText(pdf, tr(cell), width-2), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+func (r *Report) writeTableHeader(pdf *gofpdf.Fpdf, tr func(string) string) {
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for i, title := range r.Header {
+		width := r.width(i)
+		pdf.CellFormat(width, 7, FitText(pdf, tr(title), width-2), "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+func (r *Report) width(column int) float64 {
+	if column < len(r.Widths) {
+		return r.Widths[column]
+	}
+	return 190 / float64(len(r.Header))
+}
+
+// FitText shortens text with an ellipsis so it fits the given width in the
+// current font instead of spilling into the next cell.
+func FitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
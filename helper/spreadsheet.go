@@ -0,0 +1,190 @@
+package helper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Rupiah and Tanggal mark spreadsheet cells that are written as money and as
+// a calendar date. CSV gets them spelled out, XLSX keeps them numeric with an
+// Indonesian display format so they can still be summed and sorted.
+type (
+	Rupiah  float64
+	Tanggal time.Time
+)
+
+// SheetWriter writes a table row by row without holding it in memory.
+type SheetWriter interface {
+	WriteHeader(names ...string) error
+	WriteRow(cells ...any) error
+	Close() error
+}
+
+type csvSheet struct {
+	out    io.Writer
+	writer *csv.Writer
+	bom    bool
+}
+
+// NewCSVSheet writes CSV meant to be opened in Excel with an Indonesian
+// locale: a UTF-8 byte order mark so names keep their accents and ";" as the
+// separator, which is what that locale expects instead of ",".
+func NewCSVSheet(w io.Writer) SheetWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	return &csvSheet{out: w, writer: writer}
+}
+
+func (s *csvSheet) writeBOM() error {
+	if s.bom {
+		return nil
+	}
+	s.bom = true
+	_, err := io.WriteString(s.out, "\uFEFF")
+	return err
+}
+
+func (s *csvSheet) WriteHeader(names ...string) error {
+	if err := s.writeBOM(); err != nil {
+		return err
+	}
+	return s.writer.Write(names)
+}
+
+// csvText keeps a text cell from being read as a formula. Spreadsheet apps
+// evaluate cells starting with "=", "+", "-" or "@", which turns customer
+// input into formulas and a phone like +62812… into a number, so those get
+// the leading quote Excel uses to mark literal text.
+func csvText(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+func (s *csvSheet) WriteRow(cells ...any) error {
+	if err := s.writeBOM(); err != nil {
+		return err
+	}
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case string:
+			record[i] = csvText(v)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case Rupiah:
+			record[i] = FormatRupiah(float64(v))
+		case Tanggal:
+			record[i] = FormatTanggal(time.Time(v))
+		case nil:
+		default:
+			record[i] = csvText(fmt.Sprint(v))
+		}
+	}
+	if err := s.writer.Write(record); err != nil {
+		return err
+	}
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+func (s *csvSheet) Close() error {
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+type xlsxSheet struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+	header int
+	rupiah int
+	date   int
+}
+
+// NewXLSXSheet starts a workbook with a single sheet. Rows are spooled to a
+// temporary file by excelize and the workbook is written to w on Close.
+func NewXLSXSheet(w io.Writer, name string) (SheetWriter, error) {
+	file := excelize.NewFile()
+	sheet := &xlsxSheet{file: file, out: w}
+	if err := file.SetSheetName("Sheet1", name); err != nil {
+		file.Close()
+		return nil, err
+	}
+	rupiahFormat := `"Rp" #,##0`
+	dateFormat := `[$-421]d mmmm yyyy`
+	var err error
+	if sheet.header, err = file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if sheet.rupiah, err = file.NewStyle(&excelize.Style{CustomNumFmt: &rupiahFormat}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if sheet.date, err = file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if sheet.stream, err = file.NewStreamWriter(name); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return sheet, nil
+}
+
+func (s *xlsxSheet) WriteHeader(names ...string) error {
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = excelize.Cell{StyleID: s.header, Value: name}
+	}
+	return s.setRow(values)
+}
+
+func (s *xlsxSheet) WriteRow(cells ...any) error {
+	values := make([]any, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case Rupiah:
+			values[i] = excelize.Cell{StyleID: s.rupiah, Value: float64(v)}
+		case Tanggal:
+			t := time.Time(v)
+			values[i] = excelize.Cell{StyleID: s.date, Value: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+		case string:
+			// Written as an inline string cell, so a leading "=" or "+"
+			// stays text and is never stored as a formula.
+			values[i] = excelize.Cell{Value: v}
+		default:
+			values[i] = v
+		}
+	}
+	return s.setRow(values)
+}
+
+func (s *xlsxSheet) setRow(values []any) error {
+	s.row++
+	cell, err := excelize.CoordinatesToCellName(1, s.row)
+	if err != nil {
+		return err
+	}
+	return s.stream.SetRow(cell, values)
+}
+
+func (s *xlsxSheet) Close() error {
+	defer s.file.Close()
+	if err := s.stream.Flush(); err != nil {
+		return err
+	}
+	return s.file.Write(s.out)
+}
@@ -0,0 +1,54 @@
+package helper
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVSheet(t *testing.T) {
+	var buf bytes.Buffer
+	sheet := NewCSVSheet(&buf)
+	assert.NoError(t, sheet.WriteHeader("Pemesan", "Telepon", "Catatan", "Total", "Tanggal"))
+	assert.NoError(t, sheet.WriteRow("Siti; Bu", "+6281234567890", "=HYPERLINK(\"x\")", Rupiah(150000),
+		Tanggal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC))))
+	assert.NoError(t, sheet.WriteRow("@admin", "-", "biasa", 3, nil))
+	assert.NoError(t, sheet.Close())
+
+	expected := "\uFEFFPemesan;Telepon;Catatan;Total;Tanggal\n" +
+		"\"Siti; Bu\";'+6281234567890;\"'=HYPERLINK(\"\"x\"\")\";" + FormatRupiah(150000) + ";" +
+		FormatTanggal(time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)) + "\n" +
+		"'@admin;'-;biasa;3;\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestXLSXSheet(t *testing.T) {
+	var buf bytes.Buffer
+	sheet, err := NewXLSXSheet(&buf, "Pesanan")
+	assert.NoError(t, err)
+	assert.NoError(t, sheet.WriteHeader("Telepon", "Catatan", "Total"))
+	assert.NoError(t, sheet.WriteRow("+6281234567890", "=1+1", Rupiah(150000)))
+	assert.NoError(t, sheet.Close())
+
+	file, err := excelize.OpenReader(&buf)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	for cell, expected := range map[string]string{"A2": "+6281234567890", "B2": "=1+1"} {
+		value, err := file.GetCellValue("Pesanan", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+		formula, err := file.GetCellFormula("Pesanan", cell)
+		assert.NoError(t, err)
+		assert.Empty(t, formula)
+		kind, err := file.GetCellType("Pesanan", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, excelize.CellTypeInlineString, kind)
+	}
+	total, err := file.GetCellValue("Pesanan", "C2", excelize.Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "150000", total)
+}
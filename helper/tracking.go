@@ -0,0 +1,35 @@
+package helper
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// NewTrackingToken returns 32 random bytes, URL-safe encoded, so tracking
+// links cannot be guessed or enumerated.
+func NewTrackingToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// TrackingURL builds the customer-facing tracking link. TRACKING_BASE_URL
+// points at the storefront page; without it the link goes straight to the
+// API under fallback.
+func TrackingURL(fallback string, token string) string {
+	base := os.Getenv("TRACKING_BASE_URL")
+	if base == "" {
+		base = fallback
+	}
+	return strings.TrimRight(base, "/") + "/" + token
+}
+
+func QRCodePNG(content string, size int) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, size)
+}
@@ -5,11 +5,12 @@ package main
 
 import (
 	"khaira-admin/controller"
+	"khaira-admin/gateway"
 	"khaira-admin/helper"
+	"khaira-admin/region"
 	"khaira-admin/repository"
 	"khaira-admin/service"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/google/wire"
 )
 
@@ -19,10 +20,14 @@ var ServerSet = wire.NewSet(
 	service.NewServiceImpl,
 	controller.NewControllerImpl,
 	helper.NewDb,
+	region.NewIndex,
+	gateway.NewProvider,
 	NewServer,
+	NewJobs,
+	NewApplication,
 )
 
-func InitServer() (*fiber.App, func(), error) {
+func InitServer() (*Server, func(), error) {
 	wire.Build(ServerSet)
 	return nil, nil, nil
 }
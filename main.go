@@ -3,6 +3,9 @@ package main
 import (
 	"khaira-admin/controller"
 	"khaira-admin/middleware"
+	"khaira-admin/scheduler"
+	"khaira-admin/service"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -17,27 +20,60 @@ func NewServer(handler controller.Controller) *fiber.App {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "https://catering-admin.netlify.app",
 		AllowCredentials: true,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, Idempotency-Key",
+		AllowMethods:     "GET, POST, PUT, PATCH, DELETE, OPTIONS",
+		ExposeHeaders:    "X-Total-Count, X-Next-Cursor, Idempotent-Replayed",
 	}))
 
 	app.Static("/images", "/app/uploads")
 
 	app.Post("/v1/login", handler.Login)
+	app.Post("/v1/payments/notification", handler.PaymentNotification)
+	app.Get("/v1/track/:token", handler.TrackOrder)
+	app.Post("/v1/track/:token/feedback", handler.SubmitFeedback)
 
 	protectedRoute := app.Group("/api")
 	protectedRoute.Use(middleware.MyMiddleware)
 	protectedRoute.Get("/v1/orders", handler.GetOrders)
 	protectedRoute.Post("/v1/orders", handler.AddOrders)
 	protectedRoute.Put("/v1/orders/:id", handler.UpdateOrder)
+	protectedRoute.Patch("/v1/orders/:id", handler.EditOrder)
 	protectedRoute.Delete("/v1/orders/:id", handler.DeleteOrder)
+	protectedRoute.Get("/v1/orders/export", handler.ExportOrders)
+	protectedRoute.Post("/v1/orders/bulk", handler.BulkUpdateOrders)
 	protectedRoute.Get("/v1/orders/user/:username", handler.GetOrdersByUsername)
 	protectedRoute.Get("/v1/orders/:id", handler.GetOrderById)
+	protectedRoute.Get("/v1/orders/:id/history", handler.GetOrderHistory)
+	protectedRoute.Get("/v1/orders/:id/notes", handler.GetOrderNotes)
+	protectedRoute.Post("/v1/orders/:id/notes", handler.AddOrderNote)
+	protectedRoute.Get("/v1/orders/:id/payments", handler.GetPayments)
+	protectedRoute.Post("/v1/orders/:id/payments", handler.AddPayment)
+	protectedRoute.Post("/v1/orders/:id/payment-proofs", handler.UploadPaymentProof)
+	protectedRoute.Post("/v1/orders/:id/payment-link", handler.CreatePaymentLink)
+	protectedRoute.Get("/v1/orders/:id/invoice.pdf", handler.GetInvoice)
+	protectedRoute.Get("/v1/orders/:id/tracking", handler.GetTrackingLink)
+	protectedRoute.Get("/v1/orders/:id/tracking.png", handler.GetTrackingQRCode)
+	protectedRoute.Get("/v1/orders/:id/refunds", handler.GetOrderRefunds)
+	protectedRoute.Post("/v1/orders/:id/credit-notes", handler.ApplyCreditNote)
+	protectedRoute.Post("/v1/payments/:id/void", handler.VoidPayment)
+	protectedRoute.Get("/v1/payment-links", handler.GetPaymentLinks)
+	protectedRoute.Post("/v1/payment-links/:id/resolve", handler.ResolvePaymentLink)
+	protectedRoute.Post("/v1/payments/:id/refunds", handler.RequestRefund)
+	protectedRoute.Get("/v1/payment-proofs", handler.GetPaymentProofs)
+	protectedRoute.Get("/v1/payment-proofs/:id/file", handler.GetPaymentProofFile)
+	protectedRoute.Post("/v1/payment-proofs/:id/approve", handler.ApprovePaymentProof)
+	protectedRoute.Post("/v1/payment-proofs/:id/reject", handler.RejectPaymentProof)
+	protectedRoute.Get("/v1/refunds", handler.GetRefunds)
+	protectedRoute.Post("/v1/refunds/:id/approve", handler.ApproveRefund)
+	protectedRoute.Post("/v1/refunds/:id/reject", handler.RejectRefund)
+	protectedRoute.Get("/v1/credit-notes", handler.GetCreditNotes)
 
 	protectedRoute.Post("/v1/products", handler.AddProduct)
 	protectedRoute.Get("/v1/products", handler.GetProducts)
 	protectedRoute.Delete("/v1/products/:id", handler.DeleteProduct)
 	protectedRoute.Put("/v1/products/:id", handler.UpdateProduct)
+	protectedRoute.Get("/v1/feedback", handler.GetFeedback)
+	protectedRoute.Put("/v1/feedback/:id/status", handler.ModerateFeedback)
 
 	protectedRoute.Get("/v1/users", handler.GetUsers)
 	protectedRoute.Get("/v1/users/:username", handler.GetUserByUsername)
@@ -45,16 +81,86 @@ func NewServer(handler controller.Controller) *fiber.App {
 
 	protectedRoute.Get("/v1/logs", handler.GetLog)
 
+	protectedRoute.Get("/v1/production/capacities", handler.GetDailyCapacities)
+	protectedRoute.Put("/v1/production/capacities", handler.SaveDailyCapacity)
+	protectedRoute.Delete("/v1/production/capacities/:category", handler.DeleteDailyCapacity)
+	protectedRoute.Get("/v1/production/settings", handler.GetProductionSettings)
+	protectedRoute.Put("/v1/production/settings", handler.UpdateProductionSettings)
+	protectedRoute.Get("/v1/production/blackouts", handler.GetBlackoutDates)
+	protectedRoute.Post("/v1/production/blackouts", handler.AddBlackoutDate)
+	protectedRoute.Delete("/v1/production/blackouts/:date", handler.DeleteBlackoutDate)
+	protectedRoute.Get("/v1/reservation-policies", handler.GetReservationPolicies)
+	protectedRoute.Put("/v1/reservation-policies", handler.SaveReservationPolicy)
+
+	protectedRoute.Get("/v1/delivery-zones", handler.GetDeliveryZones)
+	protectedRoute.Post("/v1/delivery-zones", handler.AddDeliveryZone)
+	protectedRoute.Put("/v1/delivery-zones/:id", handler.UpdateDeliveryZone)
+	protectedRoute.Delete("/v1/delivery-zones/:id", handler.DeleteDeliveryZone)
+
+	protectedRoute.Get("/v1/regions", handler.GetRegions)
+	protectedRoute.Get("/v1/regions/search", handler.SearchRegions)
+	protectedRoute.Get("/v1/regions/:code", handler.GetRegion)
+
+	protectedRoute.Get("/v1/reports/production", handler.GetProductionSheet)
+	protectedRoute.Get("/v1/reports/manifest", handler.GetDeliveryManifest)
+
+	protectedRoute.Get("/v1/quotations", handler.GetQuotations)
+	protectedRoute.Post("/v1/quotations", handler.AddQuotation)
+	protectedRoute.Get("/v1/quotations/:id", handler.GetQuotation)
+	protectedRoute.Put("/v1/quotations/:id", handler.ReviseQuotation)
+	protectedRoute.Put("/v1/quotations/:id/status", handler.UpdateQuotationStatus)
+	protectedRoute.Post("/v1/quotations/:id/convert", handler.ConvertQuotation)
+	protectedRoute.Get("/v1/quotations/:id/quotation.pdf", handler.GetQuotationPDF)
+
+	protectedRoute.Get("/v1/vouchers", handler.GetVouchers)
+	protectedRoute.Post("/v1/vouchers", handler.AddVoucher)
+	protectedRoute.Put("/v1/vouchers/:id", handler.UpdateVoucher)
+	protectedRoute.Delete("/v1/vouchers/:id", handler.DeactivateVoucher)
+	protectedRoute.Get("/v1/discount-rules", handler.GetDiscountRules)
+	protectedRoute.Post("/v1/discount-rules", handler.AddDiscountRule)
+	protectedRoute.Put("/v1/discount-rules/:id", handler.UpdateDiscountRule)
+	protectedRoute.Delete("/v1/discount-rules/:id", handler.DeleteDiscountRule)
+
+	protectedRoute.Get("/v1/subscriptions", handler.GetSubscriptions)
+	protectedRoute.Post("/v1/subscriptions", handler.AddSubscription)
+	protectedRoute.Get("/v1/subscriptions/:id", handler.GetSubscription)
+	protectedRoute.Post("/v1/subscriptions/:id/pause", handler.PauseSubscription)
+	protectedRoute.Post("/v1/subscriptions/:id/resume", handler.ResumeSubscription)
+	protectedRoute.Post("/v1/subscriptions/:id/cancel", handler.CancelSubscription)
+	protectedRoute.Post("/v1/subscriptions/:id/skips", handler.SkipSubscriptionDate)
+	protectedRoute.Delete("/v1/subscriptions/:id/skips/:date", handler.UnskipSubscriptionDate)
+
 	return app
 }
 
+type Server struct {
+	App  *fiber.App
+	Jobs *scheduler.Scheduler
+}
+
+func NewJobs(svc service.Service) *scheduler.Scheduler {
+	jobs := scheduler.New()
+	jobs.Every("materialise subscriptions", time.Hour, svc.MaterialiseSubscriptions)
+	jobs.Every("release expired reservations", 5*time.Minute, svc.ReleaseExpiredReservations)
+	jobs.Every("purge idempotency keys", time.Hour, svc.PurgeIdempotencyKeys)
+	jobs.Every("assign order codes", time.Hour, svc.AssignOrderCodes)
+	return jobs
+}
+
+func NewApplication(app *fiber.App, jobs *scheduler.Scheduler) *Server {
+	return &Server{App: app, Jobs: jobs}
+}
+
 func main() {
-	app, cleanup, err := InitServer()
+	server, cleanup, err := InitServer()
 	if err != nil {
 		panic(err)
 	}
 	defer cleanup()
 
-	app.Listen(":8082")
+	server.Jobs.Start()
+	defer server.Jobs.Stop()
+
+	server.App.Listen(":8082")
 
 }
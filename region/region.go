@@ -0,0 +1,270 @@
+package region
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"khaira-admin/domain"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The embedded file uses the Kemendagri code format (32, 32.01, 32.01.01,
+// 32.01.01.2001) and only covers the service area: every kecamatan of
+// Kabupaten Bogor, Kota Bogor and Kota Depok, with the desa of the kecamatan
+// we deliver to most. Addresses are validated against that area only. A
+// kecamatan outside it is rejected; a desa is checked only where its
+// kecamatan has desa listed, elsewhere it is kept as typed. A complete dataset
+// with the same two columns can replace it without code changes.
+//
+//go:embed data/wilayah.csv
+var dataFiles embed.FS
+
+var levels = []string{
+	domain.RegionLevelProvinsi,
+	domain.RegionLevelKabupaten,
+	domain.RegionLevelKecamatan,
+	domain.RegionLevelDesa,
+}
+
+type Index struct {
+	regions  []*domain.Region
+	byCode   map[string]*domain.Region
+	children map[string][]*domain.Region
+	keys     map[string]string
+}
+
+func NewIndex() (*Index, error) {
+	file, err := dataFiles.Open("data/wilayah.csv")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+func Load(r io.Reader) (*Index, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	if _, err := reader.Read(); err != nil {
+		return nil, err
+	}
+	index := &Index{
+		byCode:   make(map[string]*domain.Region),
+		children: make(map[string][]*domain.Region),
+		keys:     make(map[string]string),
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		code := strings.TrimSpace(record[0])
+		parts := strings.Split(code, ".")
+		if len(parts) > len(levels) {
+			return nil, fmt.Errorf("invalid region code %q", code)
+		}
+		region := &domain.Region{
+			Code:       code,
+			Name:       strings.TrimSpace(record[1]),
+			Level:      levels[len(parts)-1],
+			ParentCode: strings.Join(parts[:len(parts)-1], "."),
+		}
+		index.regions = append(index.regions, region)
+		index.byCode[code] = region
+		index.children[region.ParentCode] = append(index.children[region.ParentCode], region)
+		index.keys[code] = Key(region.Name)
+	}
+	for _, children := range index.children {
+		sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	}
+	return index, nil
+}
+
+func (idx *Index) Get(code string) (*domain.Region, bool) {
+	region, ok := idx.byCode[code]
+	return region, ok
+}
+
+// Children lists the direct children of a region; an empty code lists the
+// provinces.
+func (idx *Index) Children(code string) []*domain.Region {
+	return idx.children[code]
+}
+
+// Search is used for autocomplete. Names starting with the query come before
+// names that only contain it.
+func (idx *Index) Search(query string, level string, parentCode string, limit int) []*domain.Region {
+	key := Key(query)
+	var prefix, contains []*domain.Region
+	for _, region := range idx.regions {
+		if level != "" && region.Level != level {
+			continue
+		}
+		if parentCode != "" && !strings.HasPrefix(region.Code, parentCode+".") {
+			continue
+		}
+		name := idx.keys[region.Code]
+		switch {
+		case strings.HasPrefix(name, key):
+			prefix = append(prefix, region)
+		case strings.Contains(name, key):
+			contains = append(contains, region)
+		}
+	}
+	result := append(prefix, contains...)
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// ResolveAddress maps free-text kecamatan and desa names to regions. It
+// tolerates prefixes such as "Kec." or "Ds.", different casing and spacing,
+// and small typos. When a kecamatan name exists in several kabupaten the desa
+// decides which one is meant. The desa is nil when it was left empty or when
+// the dataset does not list the desa of that kecamatan.
+func (idx *Index) ResolveAddress(kecamatan string, desa string) (*domain.Region, *domain.Region, error) {
+	candidates := idx.match(kecamatan, domain.RegionLevelKecamatan, "")
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("kecamatan %q tidak dikenal atau di luar area layanan", kecamatan)
+	}
+	if strings.TrimSpace(desa) == "" {
+		if len(candidates) > 1 {
+			return nil, nil, fmt.Errorf("kecamatan %q ada di lebih dari satu kabupaten", kecamatan)
+		}
+		return candidates[0], nil, nil
+	}
+
+	var foundKecamatan, foundDesa *domain.Region
+	var unlisted []*domain.Region
+	for _, candidate := range candidates {
+		if len(idx.children[candidate.Code]) == 0 {
+			unlisted = append(unlisted, candidate)
+			continue
+		}
+		matches := idx.match(desa, domain.RegionLevelDesa, candidate.Code)
+		if len(matches) == 0 {
+			continue
+		}
+		if len(matches) > 1 || foundDesa != nil {
+			return nil, nil, fmt.Errorf("desa %q di kecamatan %q tidak jelas, pilih dari daftar wilayah", desa, kecamatan)
+		}
+		foundKecamatan, foundDesa = candidate, matches[0]
+	}
+	if foundDesa == nil && len(unlisted) == 1 {
+		return unlisted[0], nil, nil
+	}
+	if foundDesa == nil && len(unlisted) > 1 {
+		return nil, nil, fmt.Errorf("kecamatan %q ada di lebih dari satu kabupaten", kecamatan)
+	}
+	if foundDesa == nil {
+		return nil, nil, fmt.Errorf("desa %q tidak ditemukan di kecamatan %s", desa, candidates[0].Name)
+	}
+	return foundKecamatan, foundDesa, nil
+}
+
+// match returns the regions whose name equals the input after normalisation,
+// or failing that, the regions with the smallest edit distance within the
+// allowed number of typos.
+func (idx *Index) match(name string, level string, parentCode string) []*domain.Region {
+	key := Key(name)
+	if key == "" {
+		return nil
+	}
+	var pool []*domain.Region
+	if parentCode != "" {
+		pool = idx.children[parentCode]
+	} else {
+		pool = idx.regions
+	}
+
+	var exact, fuzzy []*domain.Region
+	best := maxTypos(key) + 1
+	for _, region := range pool {
+		if region.Level != level {
+			continue
+		}
+		candidate := idx.keys[region.Code]
+		if candidate == key {
+			exact = append(exact, region)
+			continue
+		}
+		distance := levenshtein(candidate, key)
+		if distance < best {
+			best = distance
+			fuzzy = []*domain.Region{region}
+		} else if distance == best {
+			fuzzy = append(fuzzy, region)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return fuzzy
+}
+
+var prefixes = []string{
+	"provinsi", "prov",
+	"kabupaten", "kab",
+	"kota",
+	"kecamatan", "kec",
+	"kelurahan", "kel",
+	"desa", "ds",
+}
+
+// Key reduces a region name to lower-case letters and digits without the
+// administrative prefix, so "Kec. Bojong Gede" and "bojonggede" compare equal.
+// "Kota" is kept when it is the whole name.
+func Key(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && isPrefix(words[0]) {
+		words = words[1:]
+	}
+	return strings.Join(words, "")
+}
+
+func isPrefix(word string) bool {
+	for _, prefix := range prefixes {
+		if word == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+func maxTypos(key string) int {
+	if len(key) <= 5 {
+		return 1
+	}
+	return 2
+}
+
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,111 @@
+package region
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAddress(t *testing.T) {
+	index, err := NewIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		kecamatan         string
+		desa              string
+		expectedErr       bool
+		expectedKecamatan string
+		expectedDesa      string
+	}{
+		{
+			name:              "exact name",
+			kecamatan:         "Cibinong",
+			desa:              "Pakansari",
+			expectedKecamatan: "32.01.01",
+			expectedDesa:      "32.01.01.1007",
+		},
+		{
+			name:              "prefix and casing",
+			kecamatan:         "Kec. cibinong",
+			desa:              "kel. PAKANSARI",
+			expectedKecamatan: "32.01.01",
+			expectedDesa:      "32.01.01.1007",
+		},
+		{
+			name:              "spacing",
+			kecamatan:         "Bojong Gede",
+			desa:              "Ds. Rawapanjang",
+			expectedKecamatan: "32.01.13",
+			expectedDesa:      "32.01.13.2006",
+		},
+		{
+			name:              "typo",
+			kecamatan:         "Cibinog",
+			desa:              "Karadenan",
+			expectedKecamatan: "32.01.01",
+			expectedDesa:      "32.01.01.1002",
+		},
+		{
+			name:              "kecamatan only",
+			kecamatan:         "Tajurhalang",
+			expectedKecamatan: "32.01.37",
+		},
+		{
+			name:              "desa not listed for kecamatan",
+			kecamatan:         "Tajurhalang",
+			desa:              "Citayam",
+			expectedKecamatan: "32.01.37",
+		},
+		{
+			name:        "unknown kecamatan",
+			kecamatan:   "Kebayoran Baru",
+			desa:        "Senayan",
+			expectedErr: true,
+		},
+		{
+			name:        "desa outside kecamatan",
+			kecamatan:   "Cibinong",
+			desa:        "Cilebut Barat",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kecamatan, desa, err := index.ResolveAddress(tt.kecamatan, tt.desa)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedKecamatan, kecamatan.Code)
+			if tt.expectedDesa == "" {
+				assert.Nil(t, desa)
+			} else {
+				assert.Equal(t, tt.expectedDesa, desa.Code)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	index, err := NewIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := index.Search("cil", "desa", "", 10)
+	var names []string
+	for _, region := range result {
+		names = append(names, region.Name)
+	}
+	assert.Equal(t, []string{"Cilebut Barat", "Cilebut Timur"}, names)
+
+	provinces := index.Children("")
+	assert.Len(t, provinces, 1)
+	assert.Equal(t, "Jawa Barat", provinces[0].Name)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) SetOrderCourier(ctx context.Context, tx *sql.Tx, id string, courier string) error {
+	result, err := tx.ExecContext(ctx, "UPDATE orders SET courier = ? WHERE id = ?", courier, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("set order courier", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("no rows updated")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrOrderCodeTaken is returned when another order already holds the code
+// being written.
+var ErrOrderCodeTaken = errors.New("order code already taken")
+
+// isDuplicateKey reports whether MySQL refused a write because the value is
+// already in the unique index named key.
+func isDuplicateKey(err error, key string) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, key)
+}
+
+func (repo *RepositoryImpl) GetOrderByCode(ctx context.Context, db *sql.DB, code string) (*domain.Orders, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE code = ?"
+	var order domain.Orders
+	if err := scanOrder(db.QueryRowContext(ctx, query, code), &order); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.GetLogger("repository-log").Log("get order by code", "error", err.Error())
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (repo *RepositoryImpl) OrderCodeExists(ctx context.Context, tx *sql.Tx, code string) (bool, error) {
+	query := "SELECT COUNT(*) FROM orders WHERE code = ?"
+	var count int
+	if err := tx.QueryRowContext(ctx, query, code).Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("order code exists", "error", err.Error())
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetOrdersWithoutCode returns up to limit ids of orders placed before order
+// codes existed, oldest first.
+func (repo *RepositoryImpl) GetOrdersWithoutCode(ctx context.Context, db *sql.DB, limit int) ([]string, error) {
+	query := "SELECT id FROM orders WHERE code IS NULL ORDER BY created_at LIMIT ?"
+	rows, err := db.QueryContext(ctx, query, limit)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get orders without code", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			logger.GetLogger("repository-log").Log("get orders without code", "error", err.Error())
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (repo *RepositoryImpl) SetOrderCode(ctx context.Context, tx *sql.Tx, orderId string, code string) error {
+	query := "UPDATE orders SET code = ? WHERE id = ? AND code IS NULL"
+	result, err := tx.ExecContext(ctx, query, code, orderId)
+	if isDuplicateKey(err, "uq_orders_code") {
+		return ErrOrderCodeTaken
+	}
+	if err != nil {
+		logger.GetLogger("repository-log").Log("set order code", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("order not found or already has a code")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+const creditNoteColumns = "id, username, order_id, amount, balance, issued_by, created_at"
+
+func scanCreditNote(row interface{ Scan(...any) error }, note *domain.CreditNote) error {
+	return row.Scan(&note.Id, &note.Username, &note.OrderId, &note.Amount, &note.Balance, &note.IssuedBy, &note.CreatedAt)
+}
+
+func (repo *RepositoryImpl) AddCreditNote(ctx context.Context, tx *sql.Tx, entity *domain.CreditNote) error {
+	query := "INSERT INTO credit_notes(id, username, order_id, amount, balance, issued_by) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Username, entity.OrderId, entity.Amount, entity.Balance, entity.IssuedBy)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add credit note", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// GetCreditNotes lists a customer's credit notes, or every credit note with
+// a balance left when username is empty.
+func (repo *RepositoryImpl) GetCreditNotes(ctx context.Context, db *sql.DB, username string) ([]*domain.CreditNote, error) {
+	query := "SELECT " + creditNoteColumns + " FROM credit_notes WHERE balance > 0 ORDER BY created_at"
+	var args []interface{}
+	if username != "" {
+		query = "SELECT " + creditNoteColumns + " FROM credit_notes WHERE username = ? ORDER BY created_at"
+		args = append(args, username)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get credit notes", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	notes := []*domain.CreditNote{}
+	for rows.Next() {
+		var note domain.CreditNote
+		if err := scanCreditNote(rows, &note); err != nil {
+			logger.GetLogger("repository-log").Log("get credit notes", "error", err.Error())
+			return nil, err
+		}
+		notes = append(notes, &note)
+	}
+	return notes, rows.Err()
+}
+
+func (repo *RepositoryImpl) LockCreditNote(ctx context.Context, tx *sql.Tx, id string) (*domain.CreditNote, error) {
+	query := "SELECT " + creditNoteColumns + " FROM credit_notes WHERE id = ? FOR UPDATE"
+	var note domain.CreditNote
+	if err := scanCreditNote(tx.QueryRowContext(ctx, query, id), &note); err != nil {
+		logger.GetLogger("repository-log").Log("lock credit note", "error", err.Error())
+		return nil, err
+	}
+	return &note, nil
+}
+
+// AdjustCreditNoteBalance spends (negative amount) or gives back (positive
+// amount) part of a credit note. The balance never drops below zero.
+func (repo *RepositoryImpl) AdjustCreditNoteBalance(ctx context.Context, tx *sql.Tx, id string, amount float64) error {
+	query := "UPDATE credit_notes SET balance = balance + ? WHERE id = ? AND balance + ? >= 0"
+	result, err := tx.ExecContext(ctx, query, amount, id, amount)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("adjust credit note balance", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("credit note not found or balance too low")
+	}
+	return nil
+}
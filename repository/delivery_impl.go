@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) GetDeliveryZones(ctx context.Context, db *sql.DB) ([]*domain.DeliveryZone, error) {
+	query := "SELECT id, kecamatan, desa, fee, min_order, deliverable, created_at, modified_at FROM delivery_zones ORDER BY kecamatan, desa"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get delivery zones", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var zones []*domain.DeliveryZone
+	for rows.Next() {
+		var zone domain.DeliveryZone
+		if err := rows.Scan(&zone.Id, &zone.Kecamatan, &zone.Desa, &zone.Fee, &zone.MinOrder, &zone.Deliverable, &zone.CreatedAt, &zone.ModifiedAt); err != nil {
+			logger.GetLogger("repository-log").Log("get delivery zones", "error", err.Error())
+			return nil, err
+		}
+		zones = append(zones, &zone)
+	}
+	return zones, rows.Err()
+}
+
+// FindDeliveryZone prefers a zone for the exact desa and falls back to the
+// zone that covers the whole kecamatan (stored with an empty desa).
+func (repo *RepositoryImpl) FindDeliveryZone(ctx context.Context, tx *sql.Tx, kecamatan string, desa string) (*domain.DeliveryZone, error) {
+	query := "SELECT id, kecamatan, desa, fee, min_order, deliverable FROM delivery_zones WHERE kecamatan = ? AND desa IN (?, '') ORDER BY desa = '' LIMIT 1"
+	var zone domain.DeliveryZone
+	err := tx.QueryRowContext(ctx, query, kecamatan, desa).Scan(&zone.Id, &zone.Kecamatan, &zone.Desa, &zone.Fee, &zone.MinOrder, &zone.Deliverable)
+	if err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+func (repo *RepositoryImpl) CountDeliveryZones(ctx context.Context, tx *sql.Tx) (int, error) {
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM delivery_zones").Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("count delivery zones", "error", err.Error())
+		return 0, err
+	}
+	return count, nil
+}
+
+func (repo *RepositoryImpl) AddDeliveryZone(ctx context.Context, tx *sql.Tx, entity *domain.DeliveryZone) error {
+	query := "INSERT INTO delivery_zones(id, kecamatan, desa, fee, min_order, deliverable) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Kecamatan, entity.Desa, entity.Fee, entity.MinOrder, entity.Deliverable)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add delivery zone", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateDeliveryZone(ctx context.Context, tx *sql.Tx, entity *domain.DeliveryZone, id string) error {
+	query := "UPDATE delivery_zones SET kecamatan = ?, desa = ?, fee = ?, min_order = ?, deliverable = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Kecamatan, entity.Desa, entity.Fee, entity.MinOrder, entity.Deliverable, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update delivery zone", "error", err.Error())
+		return err
+	}
+	return repo.checkUpdated(ctx, tx, result, "delivery_zones", id, "delivery zone not found")
+}
+
+func (repo *RepositoryImpl) DeleteDeliveryZone(ctx context.Context, tx *sql.Tx, id string) error {
+	query := "DELETE FROM delivery_zones WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete delivery zone", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("delivery zone not found or already deleted")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) UpdateOrderDetails(ctx context.Context, tx *sql.Tx, entity *domain.Orders) error {
+	query := "UPDATE orders SET product_id = ?, product_name = ?, variant = ?, quantity = ?, alamat = ?, kecamatan = ?, kecamatan_code = ?, desa = ?, desa_code = ?, delivery_date = ?, delivery_slot = ?, subtotal = ?, discount = ?, voucher_code = ?, delivery_fee = ?, total = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.ProductId, entity.ProductName, entity.Variant, entity.Quantity, entity.Alamat, entity.Kecamatan, entity.KecamatanCode,
+		entity.Desa, entity.DesaCode, entity.DeliveryDate, entity.DeliverySlot, entity.Subtotal, entity.Discount, entity.VoucherCode, entity.DeliveryFee, entity.Total, entity.Id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update order details", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("no rows updated")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) DeductStock(ctx context.Context, tx *sql.Tx, productId string, quantity int) error {
+	query := "UPDATE products SET stock = stock - ? WHERE id = ? AND stock >= ?"
+	result, err := tx.ExecContext(ctx, query, quantity, productId, quantity)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("deduct stock", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAff == 0 {
+		return fmt.Errorf("stok tidak mencukupi untuk produk %s", productId)
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateVoucherRedemption(ctx context.Context, tx *sql.Tx, orderId string, discount float64) error {
+	query := "UPDATE voucher_redemptions SET discount = ? WHERE order_id = ?"
+	if _, err := tx.ExecContext(ctx, query, discount, orderId); err != nil {
+		logger.GetLogger("repository-log").Log("update voucher redemption", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) IsQuotedOrder(ctx context.Context, tx *sql.Tx, orderId string) (bool, error) {
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM quotation_items WHERE order_id = ?", orderId).Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("is quoted order", "error", err.Error())
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) GetProductDetail(ctx context.Context, db *sql.DB, id string) (*domain.Domain, error) {
+	query := "SELECT id, name, COALESCE(description, ''), stock, price, category, COALESCE(image_metadata, ''), created_at, modified_at FROM products WHERE id = ?"
+	var product domain.Domain
+	err := db.QueryRowContext(ctx, query, id).Scan(&product.Id, &product.Name, &product.Description, &product.Stock, &product.Price,
+		&product.Category, &product.ImageMetadata, &product.CreatedAt, &product.ModifiedAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get product detail", "error", err.Error())
+		return nil, err
+	}
+	return &product, nil
+}
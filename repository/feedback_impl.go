@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+const feedbackColumns = "id, order_id, product_id, username, rating, product_rating, comment, status, moderated_by, moderated_at, created_at"
+
+func scanFeedback(row interface{ Scan(...any) error }, feedback *domain.Feedback) error {
+	return row.Scan(&feedback.Id, &feedback.OrderId, &feedback.ProductId, &feedback.Username, &feedback.Rating, &feedback.ProductRating,
+		&feedback.Comment, &feedback.Status, &feedback.ModeratedBy, &feedback.ModeratedAt, &feedback.CreatedAt)
+}
+
+func (repo *RepositoryImpl) AddFeedback(ctx context.Context, tx *sql.Tx, entity *domain.Feedback) error {
+	query := "INSERT INTO feedback(id, order_id, product_id, username, rating, product_rating, comment, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.OrderId, entity.ProductId, entity.Username, entity.Rating, entity.ProductRating, entity.Comment, entity.Status)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add feedback", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) HasFeedback(ctx context.Context, tx *sql.Tx, orderId string) (bool, error) {
+	query := "SELECT COUNT(*) FROM feedback WHERE order_id = ?"
+	var count int
+	if err := tx.QueryRowContext(ctx, query, orderId).Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("has feedback", "error", err.Error())
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetFeedback lists feedback with the given status, newest first, optionally
+// narrowed to one product.
+func (repo *RepositoryImpl) GetFeedback(ctx context.Context, db *sql.DB, status string, productId string) ([]*domain.Feedback, error) {
+	query := "SELECT " + feedbackColumns + " FROM feedback WHERE status = ?"
+	args := []interface{}{status}
+	if productId != "" {
+		query += " AND product_id = ?"
+		args = append(args, productId)
+	}
+	query += " ORDER BY created_at DESC"
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get feedback", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	feedback := []*domain.Feedback{}
+	for rows.Next() {
+		var entry domain.Feedback
+		if err := scanFeedback(rows, &entry); err != nil {
+			logger.GetLogger("repository-log").Log("get feedback", "error", err.Error())
+			return nil, err
+		}
+		feedback = append(feedback, &entry)
+	}
+	return feedback, rows.Err()
+}
+
+func (repo *RepositoryImpl) ModerateFeedback(ctx context.Context, tx *sql.Tx, id string, status string, moderatedBy string) error {
+	query := "UPDATE feedback SET status = ?, moderated_by = ?, moderated_at = CURRENT_TIMESTAMP WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, status, moderatedBy, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("moderate feedback", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("feedback not found")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetProductRatings(ctx context.Context, db *sql.DB) ([]*domain.ProductRating, error) {
+	query := "SELECT product_id, AVG(product_rating), COUNT(*) FROM feedback WHERE status = ? GROUP BY product_id"
+	rows, err := db.QueryContext(ctx, query, domain.FeedbackStatusApproved)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get product ratings", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var ratings []*domain.ProductRating
+	for rows.Next() {
+		var rating domain.ProductRating
+		if err := rows.Scan(&rating.ProductId, &rating.AverageRating, &rating.RatingCount); err != nil {
+			logger.GetLogger("repository-log").Log("get product ratings", "error", err.Error())
+			return nil, err
+		}
+		ratings = append(ratings, &rating)
+	}
+	return ratings, rows.Err()
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+const paymentLinkColumns = "id, order_id, provider, provider_order_id, amount, paid_amount, token, redirect_url, status, transaction_id, payment_id, created_at, modified_at"
+
+func scanPaymentLink(row interface{ Scan(...any) error }, link *domain.PaymentLink) error {
+	return row.Scan(&link.Id, &link.OrderId, &link.Provider, &link.ProviderOrderId, &link.Amount, &link.PaidAmount,
+		&link.Token, &link.RedirectURL, &link.Status, &link.TransactionId, &link.PaymentId, &link.CreatedAt, &link.ModifiedAt)
+}
+
+func (repo *RepositoryImpl) AddPaymentLink(ctx context.Context, tx *sql.Tx, entity *domain.PaymentLink) error {
+	query := "INSERT INTO payment_links(id, order_id, provider, provider_order_id, amount, token, redirect_url, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.OrderId, entity.Provider, entity.ProviderOrderId, entity.Amount, entity.Token, entity.RedirectURL, entity.Status)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add payment link", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) LockPaymentLink(ctx context.Context, tx *sql.Tx, provider string, providerOrderId string) (*domain.PaymentLink, error) {
+	query := "SELECT " + paymentLinkColumns + " FROM payment_links WHERE provider = ? AND provider_order_id = ? FOR UPDATE"
+	var link domain.PaymentLink
+	if err := scanPaymentLink(tx.QueryRowContext(ctx, query, provider, providerOrderId), &link); err != nil {
+		logger.GetLogger("repository-log").Log("lock payment link", "error", err.Error())
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (repo *RepositoryImpl) LockPaymentLinkById(ctx context.Context, tx *sql.Tx, id string) (*domain.PaymentLink, error) {
+	query := "SELECT " + paymentLinkColumns + " FROM payment_links WHERE id = ? FOR UPDATE"
+	var link domain.PaymentLink
+	if err := scanPaymentLink(tx.QueryRowContext(ctx, query, id), &link); err != nil {
+		logger.GetLogger("repository-log").Log("lock payment link by id", "error", err.Error())
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (repo *RepositoryImpl) GetPaymentLinks(ctx context.Context, db *sql.DB, status string) ([]*domain.PaymentLink, error) {
+	query := "SELECT " + paymentLinkColumns + " FROM payment_links WHERE status = ? ORDER BY created_at"
+	rows, err := db.QueryContext(ctx, query, status)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get payment links", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	links := []*domain.PaymentLink{}
+	for rows.Next() {
+		var link domain.PaymentLink
+		if err := scanPaymentLink(rows, &link); err != nil {
+			logger.GetLogger("repository-log").Log("get payment links", "error", err.Error())
+			return nil, err
+		}
+		links = append(links, &link)
+	}
+	return links, rows.Err()
+}
+
+// UpdatePaymentLink saves the link state. A provider resending the same
+// notification leaves the row unchanged, which is not an error.
+func (repo *RepositoryImpl) UpdatePaymentLink(ctx context.Context, tx *sql.Tx, entity *domain.PaymentLink) error {
+	query := "UPDATE payment_links SET status = ?, paid_amount = ?, transaction_id = ?, payment_id = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Status, entity.PaidAmount, entity.TransactionId, entity.PaymentId, entity.Id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update payment link", "error", err.Error())
+		return err
+	}
+	return repo.checkUpdated(ctx, tx, result, "payment_links", entity.Id, "payment link not found")
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) AddOrderHistory(ctx context.Context, tx *sql.Tx, entity *domain.OrderHistory) error {
+	changes, err := json.Marshal(entity.Changes)
+	if err != nil {
+		return err
+	}
+	query := "INSERT INTO order_history(order_id, action, changes, changed_by) VALUES (?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, entity.OrderId, entity.Action, string(changes), entity.ChangedBy)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add order history", "error", err.Error())
+		return err
+	}
+	entity.Id, err = result.LastInsertId()
+	return err
+}
+
+func scanOrderHistory(row interface{ Scan(...any) error }, entry *domain.OrderHistory) error {
+	var changes string
+	if err := row.Scan(&entry.Id, &entry.OrderId, &entry.Action, &changes, &entry.ChangedBy, &entry.CreatedAt); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(changes), &entry.Changes)
+}
+
+func (repo *RepositoryImpl) GetOrderHistory(ctx context.Context, db *sql.DB, orderId string) ([]*domain.OrderHistory, error) {
+	query := "SELECT id, order_id, action, changes, changed_by, created_at FROM order_history WHERE order_id = ? ORDER BY id"
+	rows, err := db.QueryContext(ctx, query, orderId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get order history", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	history := []*domain.OrderHistory{}
+	for rows.Next() {
+		var entry domain.OrderHistory
+		if err := scanOrderHistory(rows, &entry); err != nil {
+			logger.GetLogger("repository-log").Log("get order history", "error", err.Error())
+			return nil, err
+		}
+		history = append(history, &entry)
+	}
+	return history, rows.Err()
+}
+
+// GetOrderHistories returns the history of the given orders, oldest entry
+// first.
+func (repo *RepositoryImpl) GetOrderHistories(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderHistory, error) {
+	if len(orderIds) == 0 {
+		return []*domain.OrderHistory{}, nil
+	}
+	query := "SELECT id, order_id, action, changes, changed_by, created_at FROM order_history WHERE order_id IN (" + inPlaceholders(len(orderIds)) + ") ORDER BY id"
+	args := make([]interface{}, len(orderIds))
+	for i, id := range orderIds {
+		args[i] = id
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get order histories", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	history := []*domain.OrderHistory{}
+	for rows.Next() {
+		var entry domain.OrderHistory
+		if err := scanOrderHistory(rows, &entry); err != nil {
+			logger.GetLogger("repository-log").Log("get order histories", "error", err.Error())
+			return nil, err
+		}
+		history = append(history, &entry)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"time"
+)
+
+func (repo *RepositoryImpl) GetIdempotencyKey(ctx context.Context, db *sql.DB, key string, now time.Time) (*domain.IdempotencyKey, error) {
+	query := "SELECT idempotency_key, fingerprint, order_id, response_status, created_at, expires_at FROM idempotency_keys WHERE idempotency_key = ? AND expires_at > ?"
+	var record domain.IdempotencyKey
+	err := db.QueryRowContext(ctx, query, key, now).Scan(&record.Key, &record.Fingerprint, &record.OrderId, &record.ResponseStatus, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// ClaimIdempotencyKey inserts the key unless a live entry already exists. A
+// concurrent request holding the same key blocks here until it finishes, and
+// false is returned when the key turned out to be taken.
+func (repo *RepositoryImpl) ClaimIdempotencyKey(ctx context.Context, tx *sql.Tx, entity *domain.IdempotencyKey) (bool, error) {
+	_, err := tx.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE idempotency_key = ? AND expires_at <= ?", entity.Key, entity.CreatedAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("claim idempotency key", "error", err.Error())
+		return false, err
+	}
+	query := "INSERT IGNORE INTO idempotency_keys(idempotency_key, fingerprint, response_status, created_at, expires_at) VALUES (?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, entity.Key, entity.Fingerprint, entity.ResponseStatus, entity.CreatedAt, entity.ExpiresAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("claim idempotency key", "error", err.Error())
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		logger.GetLogger("repository-log").Log("claim idempotency key", "error", err.Error())
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (repo *RepositoryImpl) CompleteIdempotencyKey(ctx context.Context, tx *sql.Tx, key string, orderId string) error {
+	query := "UPDATE idempotency_keys SET order_id = ? WHERE idempotency_key = ?"
+	if _, err := tx.ExecContext(ctx, query, orderId, key); err != nil {
+		logger.GetLogger("repository-log").Log("complete idempotency key", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) DeleteExpiredIdempotencyKeys(ctx context.Context, db *sql.DB, now time.Time) error {
+	if _, err := db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= ?", now); err != nil {
+		logger.GetLogger("repository-log").Log("delete expired idempotency keys", "error", err.Error())
+		return err
+	}
+	return nil
+}
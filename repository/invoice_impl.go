@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/logger"
+)
+
+// NextInvoiceNumber bumps the counter for a period inside the caller's
+// transaction. The row stays locked until commit, and a rollback hands the
+// number back, so the sequence has no gaps.
+func (repo *RepositoryImpl) NextInvoiceNumber(ctx context.Context, tx *sql.Tx, period string) (int, error) {
+	query := "INSERT INTO invoice_sequences(period, last_number) VALUES (?, LAST_INSERT_ID(1)) ON DUPLICATE KEY UPDATE last_number = LAST_INSERT_ID(last_number + 1)"
+	if _, err := tx.ExecContext(ctx, query, period); err != nil {
+		logger.GetLogger("repository-log").Log("next invoice number", "error", err.Error())
+		return 0, err
+	}
+	var number int
+	if err := tx.QueryRowContext(ctx, "SELECT LAST_INSERT_ID()").Scan(&number); err != nil {
+		logger.GetLogger("repository-log").Log("next invoice number", "error", err.Error())
+		return 0, err
+	}
+	return number, nil
+}
@@ -172,36 +172,6 @@ func (_m *Repository) GetOrderByUsername(ctx context.Context, db *sql.DB, userna
 	return r0, r1
 }
 
-// GetOrders provides a mock function with given fields: ctx, db
-func (_m *Repository) GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orders, error) {
-	ret := _m.Called(ctx, db)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetOrders")
-	}
-
-	var r0 []*domain.Orders
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) ([]*domain.Orders, error)); ok {
-		return rf(ctx, db)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, *sql.DB) []*domain.Orders); ok {
-		r0 = rf(ctx, db)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*domain.Orders)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, *sql.DB) error); ok {
-		r1 = rf(ctx, db)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // GetProducts provides a mock function with given fields: ctx, db
 func (_m *Repository) GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Domain, error) {
 	ret := _m.Called(ctx, db)
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) AddOrderNote(ctx context.Context, tx *sql.Tx, entity *domain.OrderNote) error {
+	query := "INSERT INTO order_notes(id, order_id, parent_id, author, body) VALUES (?, ?, NULLIF(?, ''), ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, entity.Id, entity.OrderId, entity.ParentId, entity.Author, entity.Body); err != nil {
+		logger.GetLogger("repository-log").Log("add order note", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetOrderNote(ctx context.Context, tx *sql.Tx, id string) (*domain.OrderNote, error) {
+	query := "SELECT id, order_id, COALESCE(parent_id, ''), author, body, created_at FROM order_notes WHERE id = ?"
+	var note domain.OrderNote
+	err := tx.QueryRowContext(ctx, query, id).Scan(&note.Id, &note.OrderId, &note.ParentId, &note.Author, &note.Body, &note.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// GetOrderNotes returns the notes of the given orders in the order they were
+// written.
+func (repo *RepositoryImpl) GetOrderNotes(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderNote, error) {
+	if len(orderIds) == 0 {
+		return []*domain.OrderNote{}, nil
+	}
+	query := "SELECT id, order_id, COALESCE(parent_id, ''), author, body, created_at FROM order_notes WHERE order_id IN (" + inPlaceholders(len(orderIds)) + ") ORDER BY created_at, id"
+	args := make([]interface{}, len(orderIds))
+	for i, id := range orderIds {
+		args[i] = id
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get order notes", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	notes := []*domain.OrderNote{}
+	for rows.Next() {
+		var note domain.OrderNote
+		if err := rows.Scan(&note.Id, &note.OrderId, &note.ParentId, &note.Author, &note.Body, &note.CreatedAt); err != nil {
+			logger.GetLogger("repository-log").Log("get order notes", "error", err.Error())
+			return nil, err
+		}
+		notes = append(notes, &note)
+	}
+	return notes, rows.Err()
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+// paidAmountColumn is what the customer has paid and kept: the payments that
+// were not voided less the refunds that were approved.
+const paidAmountColumn = "((SELECT COALESCE(SUM(pay.amount), 0) FROM payments pay WHERE pay.order_id = orders.id AND pay.voided_at IS NULL) - " +
+	"(SELECT COALESCE(SUM(ref.amount), 0) FROM refunds ref WHERE ref.order_id = orders.id AND ref.status = '" + domain.RefundStatusApproved + "'))"
+
+func (repo *RepositoryImpl) LockOrderById(ctx context.Context, tx *sql.Tx, id string) (*domain.Orders, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE id = ? FOR UPDATE"
+	var order domain.Orders
+	if err := scanOrder(tx.QueryRowContext(ctx, query, id), &order); err != nil {
+		logger.GetLogger("repository-log").Log("lock order", "error", err.Error())
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (repo *RepositoryImpl) AddPayment(ctx context.Context, tx *sql.Tx, entity *domain.Payment) error {
+	query := "INSERT INTO payments(id, order_id, method, amount, reference, proof, received_by, paid_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.OrderId, entity.Method, entity.Amount, entity.Reference, entity.Proof, entity.ReceivedBy, entity.PaidAt)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add payment", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+const paymentColumns = "id, order_id, method, amount, reference, proof, received_by, paid_at, voided_at, voided_by, void_reason, created_at"
+
+func scanPayment(row interface{ Scan(...any) error }, payment *domain.Payment) error {
+	return row.Scan(&payment.Id, &payment.OrderId, &payment.Method, &payment.Amount, &payment.Reference, &payment.Proof,
+		&payment.ReceivedBy, &payment.PaidAt, &payment.VoidedAt, &payment.VoidedBy, &payment.VoidReason, &payment.CreatedAt)
+}
+
+func (repo *RepositoryImpl) GetPaymentsByOrder(ctx context.Context, db *sql.DB, orderId string) ([]*domain.Payment, error) {
+	query := "SELECT " + paymentColumns + " FROM payments WHERE order_id = ? ORDER BY paid_at, created_at"
+	rows, err := db.QueryContext(ctx, query, orderId)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get payments by order", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var payments []*domain.Payment
+	for rows.Next() {
+		var payment domain.Payment
+		if err := scanPayment(rows, &payment); err != nil {
+			logger.GetLogger("repository-log").Log("get payments by order", "error", err.Error())
+			return nil, err
+		}
+		payments = append(payments, &payment)
+	}
+	return payments, rows.Err()
+}
+
+// GetPaymentsByOrders returns the payments of the given orders, each order's
+// payments in the order they were paid.
+func (repo *RepositoryImpl) GetPaymentsByOrders(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.Payment, error) {
+	if len(orderIds) == 0 {
+		return []*domain.Payment{}, nil
+	}
+	query := "SELECT " + paymentColumns + " FROM payments WHERE order_id IN (" + inPlaceholders(len(orderIds)) + ") ORDER BY paid_at, created_at"
+	args := make([]interface{}, len(orderIds))
+	for i, id := range orderIds {
+		args[i] = id
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get payments by orders", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	payments := []*domain.Payment{}
+	for rows.Next() {
+		var payment domain.Payment
+		if err := scanPayment(rows, &payment); err != nil {
+			logger.GetLogger("repository-log").Log("get payments by orders", "error", err.Error())
+			return nil, err
+		}
+		payments = append(payments, &payment)
+	}
+	return payments, rows.Err()
+}
+
+func (repo *RepositoryImpl) GetPaymentById(ctx context.Context, tx *sql.Tx, id string) (*domain.Payment, error) {
+	query := "SELECT " + paymentColumns + " FROM payments WHERE id = ? FOR UPDATE"
+	var payment domain.Payment
+	err := scanPayment(tx.QueryRowContext(ctx, query, id), &payment)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get payment by id", "error", err.Error())
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (repo *RepositoryImpl) VoidPayment(ctx context.Context, tx *sql.Tx, id string, voidedBy string, reason string) error {
+	query := "UPDATE payments SET voided_at = CURRENT_TIMESTAMP, voided_by = ?, void_reason = ? WHERE id = ? AND voided_at IS NULL"
+	result, err := tx.ExecContext(ctx, query, voidedBy, reason, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("void payment", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("payment not found or already voided")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) SumPayments(ctx context.Context, tx *sql.Tx, orderId string) (float64, error) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM payments WHERE order_id = ? AND voided_at IS NULL"
+	var paid float64
+	if err := tx.QueryRowContext(ctx, query, orderId).Scan(&paid); err != nil {
+		logger.GetLogger("repository-log").Log("sum payments", "error", err.Error())
+		return 0, err
+	}
+	return paid, nil
+}
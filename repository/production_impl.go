@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) GetProductById(ctx context.Context, tx *sql.Tx, id string) (*domain.Domain, error) {
+	query := "SELECT id, name, stock, price, category FROM products WHERE id = ?"
+	row := tx.QueryRowContext(ctx, query, id)
+	var product domain.Domain
+	if err := row.Scan(&product.Id, &product.Name, &product.Stock, &product.Price, &product.Category); err != nil {
+		logger.GetLogger("repository-log").Log("get product by id", "error", err.Error())
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (repo *RepositoryImpl) GetDailyCapacities(ctx context.Context, db *sql.DB) ([]*domain.DailyCapacity, error) {
+	query := "SELECT category, max_portions FROM daily_capacities ORDER BY category"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get daily capacities", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var capacities []*domain.DailyCapacity
+	for rows.Next() {
+		var capacity domain.DailyCapacity
+		if err := rows.Scan(&capacity.Category, &capacity.MaxPortions); err != nil {
+			logger.GetLogger("repository-log").Log("get daily capacities", "error", err.Error())
+			return nil, err
+		}
+		capacities = append(capacities, &capacity)
+	}
+	return capacities, rows.Err()
+}
+
+func (repo *RepositoryImpl) SaveDailyCapacity(ctx context.Context, tx *sql.Tx, entity *domain.DailyCapacity) error {
+	query := "INSERT INTO daily_capacities(category, max_portions) VALUES (?, ?) ON DUPLICATE KEY UPDATE max_portions = VALUES(max_portions)"
+	if _, err := tx.ExecContext(ctx, query, entity.Category, entity.MaxPortions); err != nil {
+		logger.GetLogger("repository-log").Log("save daily capacity", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) DeleteDailyCapacity(ctx context.Context, tx *sql.Tx, category string) error {
+	query := "DELETE FROM daily_capacities WHERE category = ?"
+	result, err := tx.ExecContext(ctx, query, category)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete daily capacity", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("capacity not found or already deleted")
+	}
+	return nil
+}
+
+// LockDailyCapacities locks the capacity rows of the given categories until
+// the transaction ends, so concurrent orders for the same day are counted one
+// after another. Categories without a row are not limited.
+func (repo *RepositoryImpl) LockDailyCapacities(ctx context.Context, tx *sql.Tx, categories []string) (map[string]int, error) {
+	if len(categories) == 0 {
+		return map[string]int{}, nil
+	}
+	query := "SELECT category, max_portions FROM daily_capacities WHERE category IN (" + inPlaceholders(len(categories)) + ") FOR UPDATE"
+	args := make([]interface{}, len(categories))
+	for i, category := range categories {
+		args[i] = category
+	}
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("lock daily capacities", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	capacities := make(map[string]int)
+	for rows.Next() {
+		var category string
+		var maxPortions int
+		if err := rows.Scan(&category, &maxPortions); err != nil {
+			logger.GetLogger("repository-log").Log("lock daily capacities", "error", err.Error())
+			return nil, err
+		}
+		capacities[category] = maxPortions
+	}
+	return capacities, rows.Err()
+}
+
+// SumBookedPortions returns the portions already ordered for a delivery date,
+// optionally narrowed to one product category. Cancelled and refunded orders
+// are ignored.
+func (repo *RepositoryImpl) SumBookedPortions(ctx context.Context, tx *sql.Tx, date domain.Date, category string) (int, error) {
+	query := "SELECT COALESCE(SUM(o.quantity), 0) FROM orders o JOIN products p ON p.id = o.product_id WHERE o.delivery_date = ? AND o.status NOT IN (" + inPlaceholders(len(domain.CancelledOrderStatuses)) + ")"
+	args := []interface{}{date}
+	for _, status := range domain.CancelledOrderStatuses {
+		args = append(args, status)
+	}
+	if category != "" {
+		query += " AND p.category = ?"
+		args = append(args, category)
+	}
+	var booked int
+	if err := tx.QueryRowContext(ctx, query, args...).Scan(&booked); err != nil {
+		logger.GetLogger("repository-log").Log("sum booked portions", "error", err.Error())
+		return 0, err
+	}
+	return booked, nil
+}
+
+func (repo *RepositoryImpl) GetProductionSettings(ctx context.Context, db *sql.DB) (*domain.ProductionSettings, error) {
+	query := "SELECT cutoff_days, cutoff_time FROM production_settings WHERE id = 1"
+	var settings domain.ProductionSettings
+	if err := db.QueryRowContext(ctx, query).Scan(&settings.CutoffDays, &settings.CutoffTime); err != nil {
+		logger.GetLogger("repository-log").Log("get production settings", "error", err.Error())
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (repo *RepositoryImpl) UpdateProductionSettings(ctx context.Context, tx *sql.Tx, entity *domain.ProductionSettings) error {
+	query := "INSERT INTO production_settings(id, cutoff_days, cutoff_time) VALUES (1, ?, ?) ON DUPLICATE KEY UPDATE cutoff_days = VALUES(cutoff_days), cutoff_time = VALUES(cutoff_time)"
+	if _, err := tx.ExecContext(ctx, query, entity.CutoffDays, entity.CutoffTime); err != nil {
+		logger.GetLogger("repository-log").Log("update production settings", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetBlackoutDates(ctx context.Context, db *sql.DB) ([]*domain.BlackoutDate, error) {
+	query := "SELECT date, reason FROM blackout_dates ORDER BY date"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get blackout dates", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var dates []*domain.BlackoutDate
+	for rows.Next() {
+		var date domain.BlackoutDate
+		if err := rows.Scan(&date.Date, &date.Reason); err != nil {
+			logger.GetLogger("repository-log").Log("get blackout dates", "error", err.Error())
+			return nil, err
+		}
+		dates = append(dates, &date)
+	}
+	return dates, rows.Err()
+}
+
+func (repo *RepositoryImpl) GetBlackoutDate(ctx context.Context, db *sql.DB, date domain.Date) (*domain.BlackoutDate, error) {
+	query := "SELECT date, reason FROM blackout_dates WHERE date = ?"
+	var blackout domain.BlackoutDate
+	if err := db.QueryRowContext(ctx, query, date).Scan(&blackout.Date, &blackout.Reason); err != nil {
+		return nil, err
+	}
+	return &blackout, nil
+}
+
+func (repo *RepositoryImpl) AddBlackoutDate(ctx context.Context, tx *sql.Tx, entity *domain.BlackoutDate) error {
+	query := "INSERT INTO blackout_dates(date, reason) VALUES (?, ?) ON DUPLICATE KEY UPDATE reason = VALUES(reason)"
+	if _, err := tx.ExecContext(ctx, query, entity.Date, entity.Reason); err != nil {
+		logger.GetLogger("repository-log").Log("add blackout date", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) DeleteBlackoutDate(ctx context.Context, tx *sql.Tx, date domain.Date) error {
+	query := "DELETE FROM blackout_dates WHERE date = ?"
+	result, err := tx.ExecContext(ctx, query, date)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete blackout date", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("blackout date not found or already deleted")
+	}
+	return nil
+}
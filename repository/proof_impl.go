@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+const proofColumns = "id, order_id, amount, bank_name, account_name, image, status, uploaded_by, reviewed_by, reviewed_at, reject_reason, payment_id, created_at"
+
+func scanPaymentProof(row interface{ Scan(...any) error }, proof *domain.PaymentProof) error {
+	return row.Scan(&proof.Id, &proof.OrderId, &proof.Amount, &proof.BankName, &proof.AccountName, &proof.Image, &proof.Status,
+		&proof.UploadedBy, &proof.ReviewedBy, &proof.ReviewedAt, &proof.RejectReason, &proof.PaymentId, &proof.CreatedAt)
+}
+
+func (repo *RepositoryImpl) AddPaymentProof(ctx context.Context, tx *sql.Tx, entity *domain.PaymentProof) error {
+	query := "INSERT INTO payment_proofs(id, order_id, amount, bank_name, account_name, image, status, uploaded_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.OrderId, entity.Amount, entity.BankName, entity.AccountName, entity.Image, entity.Status, entity.UploadedBy)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add payment proof", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetPaymentProofs(ctx context.Context, db *sql.DB, status string) ([]*domain.PaymentProof, error) {
+	query := "SELECT " + proofColumns + " FROM payment_proofs WHERE status = ? ORDER BY created_at"
+	rows, err := db.QueryContext(ctx, query, status)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get payment proofs", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var proofs []*domain.PaymentProof
+	for rows.Next() {
+		var proof domain.PaymentProof
+		if err := scanPaymentProof(rows, &proof); err != nil {
+			logger.GetLogger("repository-log").Log("get payment proofs", "error", err.Error())
+			return nil, err
+		}
+		proofs = append(proofs, &proof)
+	}
+	return proofs, rows.Err()
+}
+
+func (repo *RepositoryImpl) GetPaymentProofById(ctx context.Context, tx *sql.Tx, id string) (*domain.PaymentProof, error) {
+	query := "SELECT " + proofColumns + " FROM payment_proofs WHERE id = ? FOR UPDATE"
+	var proof domain.PaymentProof
+	if err := scanPaymentProof(tx.QueryRowContext(ctx, query, id), &proof); err != nil {
+		logger.GetLogger("repository-log").Log("get payment proof by id", "error", err.Error())
+		return nil, err
+	}
+	return &proof, nil
+}
+
+func (repo *RepositoryImpl) GetPaymentProof(ctx context.Context, db *sql.DB, id string) (*domain.PaymentProof, error) {
+	query := "SELECT " + proofColumns + " FROM payment_proofs WHERE id = ?"
+	var proof domain.PaymentProof
+	if err := scanPaymentProof(db.QueryRowContext(ctx, query, id), &proof); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logger.GetLogger("repository-log").Log("get payment proof", "error", err.Error())
+		}
+		return nil, err
+	}
+	return &proof, nil
+}
+
+func (repo *RepositoryImpl) ReviewPaymentProof(ctx context.Context, tx *sql.Tx, entity *domain.PaymentProof) error {
+	query := "UPDATE payment_proofs SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP, reject_reason = ?, payment_id = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Status, entity.ReviewedBy, entity.RejectReason, entity.PaymentId, entity.Id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("review payment proof", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("payment proof not found")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) CountPendingProofs(ctx context.Context, tx *sql.Tx, orderId string) (int, error) {
+	query := "SELECT COUNT(*) FROM payment_proofs WHERE order_id = ? AND status = ?"
+	var count int
+	if err := tx.QueryRowContext(ctx, query, orderId, domain.ProofStatusPending).Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("count pending proofs", "error", err.Error())
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+const quotationColumns = "q.id, q.revision, q.status, q.event_name, q.name, q.phone, q.username, q.alamat, q.kecamatan, q.desa, q.delivery_date, q.delivery_slot, r.delivery_fee, r.valid_until, COALESCE(r.notes, ''), r.created_by, q.created_at, q.modified_at"
+
+func scanQuotation(row interface{ Scan(...any) error }, q *domain.Quotation) error {
+	return row.Scan(&q.Id, &q.Revision, &q.Status, &q.EventName, &q.Name, &q.Phone, &q.Username, &q.Alamat, &q.Kecamatan, &q.Desa,
+		&q.DeliveryDate, &q.DeliverySlot, &q.DeliveryFee, &q.ValidUntil, &q.Notes, &q.CreatedBy, &q.CreatedAt, &q.ModifiedAt)
+}
+
+func (repo *RepositoryImpl) AddQuotation(ctx context.Context, tx *sql.Tx, entity *domain.Quotation) error {
+	query := "INSERT INTO quotations(id, revision, status, event_name, name, phone, username, alamat, kecamatan, desa, delivery_date, delivery_slot, created_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Revision, entity.Status, entity.EventName, entity.Name, entity.Phone, entity.Username,
+		entity.Alamat, entity.Kecamatan, entity.Desa, entity.DeliveryDate, entity.DeliverySlot, entity.CreatedBy)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add quotation", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateQuotation(ctx context.Context, tx *sql.Tx, entity *domain.Quotation) error {
+	query := "UPDATE quotations SET revision = ?, status = ?, event_name = ?, name = ?, phone = ?, alamat = ?, kecamatan = ?, desa = ?, delivery_date = ?, delivery_slot = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Revision, entity.Status, entity.EventName, entity.Name, entity.Phone, entity.Alamat,
+		entity.Kecamatan, entity.Desa, entity.DeliveryDate, entity.DeliverySlot, entity.Id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update quotation", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("quotation not found")
+	}
+	return nil
+}
+
+// AddQuotationRevision stores the priced part of a quotation under
+// entity.Revision. Earlier revisions are kept for reference.
+func (repo *RepositoryImpl) AddQuotationRevision(ctx context.Context, tx *sql.Tx, entity *domain.Quotation) error {
+	query := "INSERT INTO quotation_revisions(quotation_id, revision, delivery_fee, valid_until, notes, created_by) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Revision, entity.DeliveryFee, entity.ValidUntil, entity.Notes, entity.CreatedBy)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add quotation revision", "error", err.Error())
+		return err
+	}
+	itemQuery := "INSERT INTO quotation_items(quotation_id, revision, product_id, product_name, variant, quantity, unit_price) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	for _, item := range entity.Items {
+		result, err := tx.ExecContext(ctx, itemQuery, entity.Id, entity.Revision, item.ProductId, item.ProductName, item.Variant, item.Quantity, item.UnitPrice)
+		if err != nil {
+			logger.GetLogger("repository-log").Log("add quotation revision", "error", err.Error())
+			return err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		item.Id = int(id)
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetQuotations(ctx context.Context, db *sql.DB, status string) ([]*domain.Quotation, error) {
+	query := "SELECT " + quotationColumns + " FROM quotations q JOIN quotation_revisions r ON r.quotation_id = q.id AND r.revision = q.revision WHERE ? = '' OR q.status = ? ORDER BY q.created_at DESC"
+	rows, err := db.QueryContext(ctx, query, status, status)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get quotations", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var quotations []*domain.Quotation
+	for rows.Next() {
+		var quotation domain.Quotation
+		if err := scanQuotation(rows, &quotation); err != nil {
+			logger.GetLogger("repository-log").Log("get quotations", "error", err.Error())
+			return nil, err
+		}
+		quotations = append(quotations, &quotation)
+	}
+	return quotations, rows.Err()
+}
+
+// GetQuotation loads one revision of a quotation with its items; revision 0
+// means the current one.
+func (repo *RepositoryImpl) GetQuotation(ctx context.Context, db *sql.DB, id string, revision int) (*domain.Quotation, error) {
+	return repo.getQuotation(ctx, db, id, revision, "")
+}
+
+func (repo *RepositoryImpl) LockQuotation(ctx context.Context, tx *sql.Tx, id string) (*domain.Quotation, error) {
+	return repo.getQuotation(ctx, tx, id, 0, " FOR UPDATE")
+}
+
+func (repo *RepositoryImpl) getQuotation(ctx context.Context, q queryer, id string, revision int, lock string) (*domain.Quotation, error) {
+	query := "SELECT " + quotationColumns + " FROM quotations q JOIN quotation_revisions r ON r.quotation_id = q.id AND r.revision = IF(? = 0, q.revision, ?) WHERE q.id = ?" + lock
+	var quotation domain.Quotation
+	if err := scanQuotation(q.QueryRowContext(ctx, query, revision, revision, id), &quotation); err != nil {
+		logger.GetLogger("repository-log").Log("get quotation", "error", err.Error())
+		return nil, err
+	}
+	if revision != 0 {
+		quotation.Revision = revision
+	}
+
+	itemQuery := "SELECT id, product_id, product_name, variant, quantity, unit_price, COALESCE(order_id, '') FROM quotation_items WHERE quotation_id = ? AND revision = ? ORDER BY id" + lock
+	rows, err := q.QueryContext(ctx, itemQuery, quotation.Id, quotation.Revision)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get quotation", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item domain.QuotationItem
+		if err := rows.Scan(&item.Id, &item.ProductId, &item.ProductName, &item.Variant, &item.Quantity, &item.UnitPrice, &item.OrderId); err != nil {
+			logger.GetLogger("repository-log").Log("get quotation", "error", err.Error())
+			return nil, err
+		}
+		quotation.Items = append(quotation.Items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	quotation.Price()
+	return &quotation, nil
+}
+
+func (repo *RepositoryImpl) SetQuotationStatus(ctx context.Context, tx *sql.Tx, id string, status string) error {
+	query := "UPDATE quotations SET status = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, status, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("set quotation status", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("quotation not found or status unchanged")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) SetQuotationItemOrder(ctx context.Context, tx *sql.Tx, itemId int, orderId string) error {
+	query := "UPDATE quotation_items SET order_id = ? WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, orderId, itemId); err != nil {
+		logger.GetLogger("repository-log").Log("set quotation item order", "error", err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+const refundColumns = "id, order_id, payment_id, amount, method, reason, status, requested_by, reviewed_by, reviewed_at, reject_reason, credit_note_id, created_at"
+
+func scanRefund(row interface{ Scan(...any) error }, refund *domain.Refund) error {
+	return row.Scan(&refund.Id, &refund.OrderId, &refund.PaymentId, &refund.Amount, &refund.Method, &refund.Reason, &refund.Status,
+		&refund.RequestedBy, &refund.ReviewedBy, &refund.ReviewedAt, &refund.RejectReason, &refund.CreditNoteId, &refund.CreatedAt)
+}
+
+func (repo *RepositoryImpl) AddRefund(ctx context.Context, tx *sql.Tx, entity *domain.Refund) error {
+	query := "INSERT INTO refunds(id, order_id, payment_id, amount, method, reason, status, requested_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.OrderId, entity.PaymentId, entity.Amount, entity.Method, entity.Reason, entity.Status, entity.RequestedBy)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add refund", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetRefunds(ctx context.Context, db *sql.DB, status string) ([]*domain.Refund, error) {
+	return repo.queryRefunds(ctx, db, "get refunds", "SELECT "+refundColumns+" FROM refunds WHERE status = ? ORDER BY created_at", status)
+}
+
+func (repo *RepositoryImpl) GetRefundsByOrder(ctx context.Context, db *sql.DB, orderId string) ([]*domain.Refund, error) {
+	return repo.queryRefunds(ctx, db, "get refunds by order", "SELECT "+refundColumns+" FROM refunds WHERE order_id = ? ORDER BY created_at", orderId)
+}
+
+func (repo *RepositoryImpl) queryRefunds(ctx context.Context, db *sql.DB, action string, query string, args ...interface{}) ([]*domain.Refund, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log(action, "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	refunds := []*domain.Refund{}
+	for rows.Next() {
+		var refund domain.Refund
+		if err := scanRefund(rows, &refund); err != nil {
+			logger.GetLogger("repository-log").Log(action, "error", err.Error())
+			return nil, err
+		}
+		refunds = append(refunds, &refund)
+	}
+	return refunds, rows.Err()
+}
+
+func (repo *RepositoryImpl) GetRefundById(ctx context.Context, tx *sql.Tx, id string) (*domain.Refund, error) {
+	query := "SELECT " + refundColumns + " FROM refunds WHERE id = ? FOR UPDATE"
+	var refund domain.Refund
+	if err := scanRefund(tx.QueryRowContext(ctx, query, id), &refund); err != nil {
+		logger.GetLogger("repository-log").Log("get refund by id", "error", err.Error())
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (repo *RepositoryImpl) ReviewRefund(ctx context.Context, tx *sql.Tx, entity *domain.Refund) error {
+	query := "UPDATE refunds SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP, reject_reason = ?, credit_note_id = ? WHERE id = ? AND status = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Status, entity.ReviewedBy, entity.RejectReason, entity.CreditNoteId, entity.Id, domain.RefundStatusPending)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("review refund", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("refund not found or already reviewed")
+	}
+	return nil
+}
+
+// SumPaymentRefunds returns what is already being given back from a payment,
+// counting refunds still waiting for approval.
+func (repo *RepositoryImpl) SumPaymentRefunds(ctx context.Context, tx *sql.Tx, paymentId string) (float64, error) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE payment_id = ? AND status IN (?, ?)"
+	var refunded float64
+	if err := tx.QueryRowContext(ctx, query, paymentId, domain.RefundStatusPending, domain.RefundStatusApproved).Scan(&refunded); err != nil {
+		logger.GetLogger("repository-log").Log("sum payment refunds", "error", err.Error())
+		return 0, err
+	}
+	return refunded, nil
+}
+
+func (repo *RepositoryImpl) SumApprovedRefunds(ctx context.Context, tx *sql.Tx, orderId string) (float64, error) {
+	query := "SELECT COALESCE(SUM(amount), 0) FROM refunds WHERE order_id = ? AND status = ?"
+	var refunded float64
+	if err := tx.QueryRowContext(ctx, query, orderId, domain.RefundStatusApproved).Scan(&refunded); err != nil {
+		logger.GetLogger("repository-log").Log("sum approved refunds", "error", err.Error())
+		return 0, err
+	}
+	return refunded, nil
+}
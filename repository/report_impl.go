@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"strings"
+)
+
+func inPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func (repo *RepositoryImpl) GetProductionItems(ctx context.Context, db *sql.DB, date domain.Date, statuses []string) ([]*domain.ProductionItem, error) {
+	query := "SELECT o.product_id, p.name, p.category, o.variant, o.id, COALESCE(o.code, ''), COALESCE(o.invoice_number, ''), o.name, o.quantity, o.status, o.customer_note " +
+		"FROM orders o JOIN products p ON p.id = o.product_id " +
+		"WHERE o.delivery_date = ? AND o.status IN (" + inPlaceholders(len(statuses)) + ") " +
+		"ORDER BY p.category, p.name, o.product_id, o.variant, o.created_at"
+	args := []interface{}{date}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get production items", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*domain.ProductionItem
+	var current *domain.ProductionItem
+	for rows.Next() {
+		var item domain.ProductionItem
+		var order domain.ProductionOrder
+		if err := rows.Scan(&item.ProductId, &item.ProductName, &item.Category, &item.Variant, &order.OrderId, &order.Code, &order.InvoiceNumber, &order.Name, &order.Quantity, &order.Status, &order.CustomerNote); err != nil {
+			logger.GetLogger("repository-log").Log("get production items", "error", err.Error())
+			return nil, err
+		}
+		if current == nil || current.ProductId != item.ProductId || current.Variant != item.Variant {
+			current = &item
+			items = append(items, current)
+		}
+		current.Quantity += order.Quantity
+		current.Orders = append(current.Orders, &order)
+	}
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get production items", "error", err.Error())
+		return nil, err
+	}
+	return items, nil
+}
+
+func (repo *RepositoryImpl) GetManifestOrders(ctx context.Context, db *sql.DB, date domain.Date, statuses []string) ([]*domain.Orders, error) {
+	query := "SELECT id, product_id, product_name, variant, name, phone, alamat, kecamatan, desa, quantity, total, " + paidAmountColumn + ", status, delivery_slot, customer_note " +
+		"FROM orders WHERE delivery_date = ? AND status IN (" + inPlaceholders(len(statuses)) + ") " +
+		"ORDER BY kecamatan, desa, delivery_slot = '', delivery_slot, created_at"
+	args := []interface{}{date}
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get manifest orders", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.Orders
+	for rows.Next() {
+		var order domain.Orders
+		if err := rows.Scan(&order.Id, &order.ProductId, &order.ProductName, &order.Variant, &order.Name, &order.Phone,
+			&order.Alamat, &order.Kecamatan, &order.Desa, &order.Quantity, &order.Total, &order.AmountPaid, &order.Status, &order.DeliverySlot, &order.CustomerNote); err != nil {
+			logger.GetLogger("repository-log").Log("get manifest orders", "error", err.Error())
+			return nil, err
+		}
+		orders = append(orders, &order)
+	}
+	if err := rows.Err(); err != nil {
+		logger.GetLogger("repository-log").Log("get manifest orders", "error", err.Error())
+		return nil, err
+	}
+	return orders, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"khaira-admin/domain"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,7 +15,6 @@ type Repository interface {
 	GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Domain, error)
 	DeleteProduct(ctx context.Context, tx *sql.Tx, id string) error
 	UpdateProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain, id string) (*domain.Domain, error)
-	GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orders, error)
 	AddOrders(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id uuid.UUID) error
 	UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string) error
 	DeleteOrder(ctx context.Context, tx *sql.Tx, id string) error
@@ -24,4 +24,124 @@ type Repository interface {
 	GetUserByUsername(ctx context.Context, db *sql.DB, username string) (*domain.Users, error)
 	DeleteUserById(ctx context.Context, db *sql.DB, id string) error
 	GetLog(ctx context.Context) ([]*domain.Hit, error)
+	GetProductById(ctx context.Context, tx *sql.Tx, id string) (*domain.Domain, error)
+	GetDailyCapacities(ctx context.Context, db *sql.DB) ([]*domain.DailyCapacity, error)
+	SaveDailyCapacity(ctx context.Context, tx *sql.Tx, entity *domain.DailyCapacity) error
+	DeleteDailyCapacity(ctx context.Context, tx *sql.Tx, category string) error
+	LockDailyCapacities(ctx context.Context, tx *sql.Tx, categories []string) (map[string]int, error)
+	SumBookedPortions(ctx context.Context, tx *sql.Tx, date domain.Date, category string) (int, error)
+	GetProductionSettings(ctx context.Context, db *sql.DB) (*domain.ProductionSettings, error)
+	UpdateProductionSettings(ctx context.Context, tx *sql.Tx, entity *domain.ProductionSettings) error
+	GetBlackoutDates(ctx context.Context, db *sql.DB) ([]*domain.BlackoutDate, error)
+	GetBlackoutDate(ctx context.Context, db *sql.DB, date domain.Date) (*domain.BlackoutDate, error)
+	AddBlackoutDate(ctx context.Context, tx *sql.Tx, entity *domain.BlackoutDate) error
+	DeleteBlackoutDate(ctx context.Context, tx *sql.Tx, date domain.Date) error
+	GetProductionItems(ctx context.Context, db *sql.DB, date domain.Date, statuses []string) ([]*domain.ProductionItem, error)
+	GetManifestOrders(ctx context.Context, db *sql.DB, date domain.Date, statuses []string) ([]*domain.Orders, error)
+	GetDeliveryZones(ctx context.Context, db *sql.DB) ([]*domain.DeliveryZone, error)
+	FindDeliveryZone(ctx context.Context, tx *sql.Tx, kecamatan string, desa string) (*domain.DeliveryZone, error)
+	CountDeliveryZones(ctx context.Context, tx *sql.Tx) (int, error)
+	AddDeliveryZone(ctx context.Context, tx *sql.Tx, entity *domain.DeliveryZone) error
+	UpdateDeliveryZone(ctx context.Context, tx *sql.Tx, entity *domain.DeliveryZone, id string) error
+	DeleteDeliveryZone(ctx context.Context, tx *sql.Tx, id string) error
+	LockOrderById(ctx context.Context, tx *sql.Tx, id string) (*domain.Orders, error)
+	AddPayment(ctx context.Context, tx *sql.Tx, entity *domain.Payment) error
+	GetPaymentsByOrder(ctx context.Context, db *sql.DB, orderId string) ([]*domain.Payment, error)
+	GetPaymentsByOrders(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.Payment, error)
+	GetPaymentById(ctx context.Context, tx *sql.Tx, id string) (*domain.Payment, error)
+	VoidPayment(ctx context.Context, tx *sql.Tx, id string, voidedBy string, reason string) error
+	SumPayments(ctx context.Context, tx *sql.Tx, orderId string) (float64, error)
+	AddPaymentProof(ctx context.Context, tx *sql.Tx, entity *domain.PaymentProof) error
+	GetPaymentProofs(ctx context.Context, db *sql.DB, status string) ([]*domain.PaymentProof, error)
+	GetPaymentProofById(ctx context.Context, tx *sql.Tx, id string) (*domain.PaymentProof, error)
+	GetPaymentProof(ctx context.Context, db *sql.DB, id string) (*domain.PaymentProof, error)
+	ReviewPaymentProof(ctx context.Context, tx *sql.Tx, entity *domain.PaymentProof) error
+	CountPendingProofs(ctx context.Context, tx *sql.Tx, orderId string) (int, error)
+	AddPaymentLink(ctx context.Context, tx *sql.Tx, entity *domain.PaymentLink) error
+	LockPaymentLink(ctx context.Context, tx *sql.Tx, provider string, providerOrderId string) (*domain.PaymentLink, error)
+	UpdatePaymentLink(ctx context.Context, tx *sql.Tx, entity *domain.PaymentLink) error
+	LockPaymentLinkById(ctx context.Context, tx *sql.Tx, id string) (*domain.PaymentLink, error)
+	GetPaymentLinks(ctx context.Context, db *sql.DB, status string) ([]*domain.PaymentLink, error)
+	NextInvoiceNumber(ctx context.Context, tx *sql.Tx, period string) (int, error)
+	AddQuotation(ctx context.Context, tx *sql.Tx, entity *domain.Quotation) error
+	UpdateQuotation(ctx context.Context, tx *sql.Tx, entity *domain.Quotation) error
+	AddQuotationRevision(ctx context.Context, tx *sql.Tx, entity *domain.Quotation) error
+	GetQuotations(ctx context.Context, db *sql.DB, status string) ([]*domain.Quotation, error)
+	GetQuotation(ctx context.Context, db *sql.DB, id string, revision int) (*domain.Quotation, error)
+	LockQuotation(ctx context.Context, tx *sql.Tx, id string) (*domain.Quotation, error)
+	SetQuotationStatus(ctx context.Context, tx *sql.Tx, id string, status string) error
+	SetQuotationItemOrder(ctx context.Context, tx *sql.Tx, itemId int, orderId string) error
+	GetVouchers(ctx context.Context, db *sql.DB) ([]*domain.Voucher, error)
+	LockVoucherByCode(ctx context.Context, tx *sql.Tx, code string) (*domain.Voucher, error)
+	AddVoucher(ctx context.Context, tx *sql.Tx, entity *domain.Voucher) error
+	UpdateVoucher(ctx context.Context, tx *sql.Tx, entity *domain.Voucher, id string) error
+	DeactivateVoucher(ctx context.Context, tx *sql.Tx, id string) error
+	CountVoucherRedemptions(ctx context.Context, tx *sql.Tx, voucherId string, username string) (int, error)
+	RedeemVoucher(ctx context.Context, tx *sql.Tx, voucherId string, orderId string, username string, discount float64) error
+	GetDiscountRules(ctx context.Context, db *sql.DB) ([]*domain.DiscountRule, error)
+	GetApplicableDiscountRules(ctx context.Context, tx *sql.Tx, productId string, category string, quantity int, at time.Time) ([]*domain.DiscountRule, error)
+	AddDiscountRule(ctx context.Context, tx *sql.Tx, entity *domain.DiscountRule) error
+	UpdateDiscountRule(ctx context.Context, tx *sql.Tx, entity *domain.DiscountRule, id string) error
+	DeleteDiscountRule(ctx context.Context, tx *sql.Tx, id string) error
+	AddSubscription(ctx context.Context, tx *sql.Tx, entity *domain.Subscription) error
+	GetSubscriptions(ctx context.Context, db *sql.DB, status string) ([]*domain.Subscription, error)
+	GetSchedulableSubscriptions(ctx context.Context, db *sql.DB, from domain.Date, to domain.Date) ([]*domain.Subscription, error)
+	GetSubscription(ctx context.Context, db *sql.DB, id string) (*domain.Subscription, error)
+	LockSubscription(ctx context.Context, tx *sql.Tx, id string) (*domain.Subscription, error)
+	UpdateSubscriptionStatus(ctx context.Context, tx *sql.Tx, id string, status string, pausedUntil *domain.Date) error
+	GetSubscriptionSkips(ctx context.Context, db *sql.DB, id string) ([]domain.Date, error)
+	AddSubscriptionSkip(ctx context.Context, tx *sql.Tx, id string, date domain.Date) error
+	DeleteSubscriptionSkip(ctx context.Context, tx *sql.Tx, id string, date domain.Date) error
+	GetSubscriptionRuns(ctx context.Context, db *sql.DB, id string) ([]*domain.SubscriptionRun, error)
+	ClaimSubscriptionRun(ctx context.Context, tx *sql.Tx, id string, date domain.Date) (string, error)
+	CompleteSubscriptionRun(ctx context.Context, tx *sql.Tx, id string, date domain.Date, orderId string) error
+	FailSubscriptionRun(ctx context.Context, db *sql.DB, id string, date domain.Date, reason string) error
+	GetReservationPolicies(ctx context.Context, db *sql.DB) ([]*domain.ReservationPolicy, error)
+	SaveReservationPolicy(ctx context.Context, tx *sql.Tx, entity *domain.ReservationPolicy) error
+	GetReservationTTL(ctx context.Context, tx *sql.Tx, paymentMethod string) (int, error)
+	GetExpiredReservations(ctx context.Context, db *sql.DB, now time.Time, after string, limit int) ([]string, error)
+	ClearReservation(ctx context.Context, tx *sql.Tx, orderId string) error
+	RestoreStock(ctx context.Context, tx *sql.Tx, productId string, quantity int) error
+	ReleaseVoucherRedemption(ctx context.Context, tx *sql.Tx, orderId string) error
+	GetIdempotencyKey(ctx context.Context, db *sql.DB, key string, now time.Time) (*domain.IdempotencyKey, error)
+	ClaimIdempotencyKey(ctx context.Context, tx *sql.Tx, entity *domain.IdempotencyKey) (bool, error)
+	CompleteIdempotencyKey(ctx context.Context, tx *sql.Tx, key string, orderId string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, db *sql.DB, now time.Time) error
+	AddOrderHistory(ctx context.Context, tx *sql.Tx, entity *domain.OrderHistory) error
+	GetOrderHistory(ctx context.Context, db *sql.DB, orderId string) ([]*domain.OrderHistory, error)
+	GetOrderHistories(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderHistory, error)
+	UpdateOrderDetails(ctx context.Context, tx *sql.Tx, entity *domain.Orders) error
+	DeductStock(ctx context.Context, tx *sql.Tx, productId string, quantity int) error
+	UpdateVoucherRedemption(ctx context.Context, tx *sql.Tx, orderId string, discount float64) error
+	IsQuotedOrder(ctx context.Context, tx *sql.Tx, orderId string) (bool, error)
+	SearchOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) ([]*domain.Orders, error)
+	CountOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) (int, error)
+	StreamOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter, fn func(*domain.Orders) error) error
+	GetProductDetail(ctx context.Context, db *sql.DB, id string) (*domain.Domain, error)
+	SetOrderCourier(ctx context.Context, tx *sql.Tx, id string, courier string) error
+	AddOrderNote(ctx context.Context, tx *sql.Tx, entity *domain.OrderNote) error
+	GetOrderNote(ctx context.Context, tx *sql.Tx, id string) (*domain.OrderNote, error)
+	GetOrderNotes(ctx context.Context, db *sql.DB, orderIds []string) ([]*domain.OrderNote, error)
+	GetOrderByTrackingToken(ctx context.Context, db *sql.DB, token string) (*domain.Orders, error)
+	GetTrackingToken(ctx context.Context, db *sql.DB, orderId string) (string, error)
+	AddRefund(ctx context.Context, tx *sql.Tx, entity *domain.Refund) error
+	GetRefunds(ctx context.Context, db *sql.DB, status string) ([]*domain.Refund, error)
+	GetRefundsByOrder(ctx context.Context, db *sql.DB, orderId string) ([]*domain.Refund, error)
+	GetRefundById(ctx context.Context, tx *sql.Tx, id string) (*domain.Refund, error)
+	ReviewRefund(ctx context.Context, tx *sql.Tx, entity *domain.Refund) error
+	SumPaymentRefunds(ctx context.Context, tx *sql.Tx, paymentId string) (float64, error)
+	SumApprovedRefunds(ctx context.Context, tx *sql.Tx, orderId string) (float64, error)
+	AddCreditNote(ctx context.Context, tx *sql.Tx, entity *domain.CreditNote) error
+	GetCreditNotes(ctx context.Context, db *sql.DB, username string) ([]*domain.CreditNote, error)
+	LockCreditNote(ctx context.Context, tx *sql.Tx, id string) (*domain.CreditNote, error)
+	AdjustCreditNoteBalance(ctx context.Context, tx *sql.Tx, id string, amount float64) error
+	AddFeedback(ctx context.Context, tx *sql.Tx, entity *domain.Feedback) error
+	HasFeedback(ctx context.Context, tx *sql.Tx, orderId string) (bool, error)
+	GetFeedback(ctx context.Context, db *sql.DB, status string, productId string) ([]*domain.Feedback, error)
+	ModerateFeedback(ctx context.Context, tx *sql.Tx, id string, status string, moderatedBy string) error
+	GetProductRatings(ctx context.Context, db *sql.DB) ([]*domain.ProductRating, error)
+	GetOrderByCode(ctx context.Context, db *sql.DB, code string) (*domain.Orders, error)
+	OrderCodeExists(ctx context.Context, tx *sql.Tx, code string) (bool, error)
+	GetOrdersWithoutCode(ctx context.Context, db *sql.DB, limit int) ([]string, error)
+	SetOrderCode(ctx context.Context, tx *sql.Tx, orderId string, code string) error
 }
@@ -40,8 +40,8 @@ func (repo *RepositoryImpl) Login(ctx context.Context, db *sql.DB, entity *domai
 }
 
 func (repo *RepositoryImpl) AddProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain) (*domain.Domain, error) {
-	query := "INSERT INTO products(id, name, description, stock, price, image_metadata, created_at) VALUES(?, ?, ?, ?, ?, ?, ?)"
-	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.Description, entity.Stock, entity.Price, entity.ImageMetadata, entity.CreatedAt)
+	query := "INSERT INTO products(id, name, description, stock, price, category, image_metadata, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?)"
+	result, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.Description, entity.Stock, entity.Price, entity.Category, entity.ImageMetadata, entity.CreatedAt)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("add product", "error", err.Error())
 		return nil, err
@@ -55,7 +55,7 @@ func (repo *RepositoryImpl) AddProduct(ctx context.Context, tx *sql.Tx, entity *
 }
 
 func (repo *RepositoryImpl) GetProducts(ctx context.Context, db *sql.DB) ([]*domain.Domain, error) {
-	query := "SELECT id, name, description, stock, price, image_metadata, created_at, modified_at FROM products"
+	query := "SELECT id, name, description, stock, price, category, image_metadata, created_at, modified_at FROM products"
 	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("get products", "error", err.Error())
@@ -67,7 +67,7 @@ func (repo *RepositoryImpl) GetProducts(ctx context.Context, db *sql.DB) ([]*dom
 		var product domain.Domain
 		var description sql.NullString
 		var imageMetadata sql.NullString
-		err := rows.Scan(&product.Id, &product.Name, &description, &product.Stock, &product.Price, &imageMetadata, &product.CreatedAt, &product.ModifiedAt)
+		err := rows.Scan(&product.Id, &product.Name, &description, &product.Stock, &product.Price, &product.Category, &imageMetadata, &product.CreatedAt, &product.ModifiedAt)
 		if err != nil {
 			logger.GetLogger("repository-log").Log("get products", "error", err.Error())
 			return nil, err
@@ -98,8 +98,8 @@ func (repo *RepositoryImpl) DeleteProduct(ctx context.Context, tx *sql.Tx, id st
 }
 
 func (repo *RepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, entity *domain.Domain, id string) (*domain.Domain, error) {
-	query := "UPDATE products SET name = ?, description = ?, stock = ?, price = ?, modified_at = ? WHERE id = ?"
-	result, err := tx.ExecContext(ctx, query, entity.Name, entity.Description, entity.Stock, entity.Price, entity.ModifiedAt, id)
+	query := "UPDATE products SET name = ?, description = ?, stock = ?, price = ?, category = COALESCE(NULLIF(?, ''), category), modified_at = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.Description, entity.Stock, entity.Price, entity.Category, entity.ModifiedAt, id)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
 		return nil, err
@@ -113,8 +113,8 @@ func (repo *RepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, entit
 		return nil, errors.New("no rows updated")
 	}
 	var product domain.Domain
-	row := tx.QueryRowContext(ctx, "SELECT id, name, description, stock, price, created_at, modified_at FROM products WHERE id = ?", id)
-	err = row.Scan(&product.Id, &product.Name, &product.Description, &product.Stock, &product.Price, &product.CreatedAt, &product.ModifiedAt)
+	row := tx.QueryRowContext(ctx, "SELECT id, name, description, stock, price, category, created_at, modified_at FROM products WHERE id = ?", id)
+	err = row.Scan(&product.Id, &product.Name, &product.Description, &product.Stock, &product.Price, &product.Category, &product.CreatedAt, &product.ModifiedAt)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("update product", "error", err.Error())
 		return nil, err
@@ -122,32 +122,20 @@ func (repo *RepositoryImpl) UpdateProduct(ctx context.Context, tx *sql.Tx, entit
 	return &product, nil
 }
 
-func (repo *RepositoryImpl) GetOrders(ctx context.Context, db *sql.DB) ([]*domain.Orders, error) {
-	query := "SELECT id, product_id, product_name, username, name, phone, alamat, kecamatan, desa ,quantity, total, status, created_at, modified_at FROM orders"
-	rows, err := db.QueryContext(ctx, query)
+const orderColumns = "id, COALESCE(code, ''), COALESCE(invoice_number, ''), product_id, product_name, variant, username, name, phone, alamat, kecamatan, kecamatan_code, desa, desa_code, quantity, subtotal, discount, voucher_code, delivery_fee, total, " + paidAmountColumn + ", payment_method, reserved_until, status, delivery_date, delivery_slot, courier, customer_note, created_at, modified_at"
+
+// scanOrder reads a row selected with orderColumns. Every order read goes
+// through it so all endpoints return the same fields.
+func scanOrder(row interface{ Scan(...any) error }, order *domain.Orders) error {
+	err := row.Scan(&order.Id, &order.Code, &order.InvoiceNumber, &order.ProductId, &order.ProductName, &order.Variant,
+		&order.Username, &order.Name, &order.Phone,
+		&order.Alamat, &order.Kecamatan, &order.KecamatanCode, &order.Desa, &order.DesaCode, &order.Quantity,
+		&order.Subtotal, &order.Discount, &order.VoucherCode, &order.DeliveryFee, &order.Total, &order.AmountPaid, &order.PaymentMethod, &order.ReservedUntil, &order.Status, &order.DeliveryDate, &order.DeliverySlot, &order.Courier, &order.CustomerNote, &order.CreatedAt, &order.ModifiedAt)
 	if err != nil {
-		logger.GetLogger("repository-log").Log("get orders", "error", err.Error())
-		return nil, err
-	}
-	defer rows.Close()
-	var orders []*domain.Orders
-	for rows.Next() {
-		var order domain.Orders
-		err := rows.Scan(&order.Id, &order.ProductId, &order.ProductName,
-			&order.Username, &order.Name, &order.Phone,
-			&order.Alamat, &order.Kecamatan, &order.Desa, &order.Quantity,
-			&order.Total, &order.Status, &order.CreatedAt, &order.ModifiedAt)
-		if err != nil {
-			logger.GetLogger("repository-log").Log("get orders", "error", err.Error())
-			return nil, err
-		}
-		orders = append(orders, &order)
-	}
-	if err := rows.Err(); err != nil {
-		logger.GetLogger("repository-log").Log("get orders", "error", err.Error())
-		return nil, err
+		return err
 	}
-	return orders, nil
+	order.PaymentState = domain.PaymentStateOf(order.Total, order.AmountPaid)
+	return nil
 }
 
 func (repo *RepositoryImpl) UpdateOrder(ctx context.Context, tx *sql.Tx, entity *domain.Orders, id string) error {
@@ -164,8 +152,10 @@ func (repo *RepositoryImpl) UpdateOrder(ctx context.Context, tx *sql.Tx, entity
 	return nil
 }
 
+// DeleteOrder removes an order that was never invoiced. Invoice numbers are
+// sequential, so an invoiced order is cancelled instead of leaving a gap.
 func (repo *RepositoryImpl) DeleteOrder(ctx context.Context, tx *sql.Tx, id string) error {
-	query := "DELETE FROM orders WHERE id = ?"
+	query := "DELETE FROM orders WHERE id = ? AND invoice_number IS NULL"
 	result, err := tx.ExecContext(ctx, query, id)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("delete order", "error", err.Error())
@@ -209,7 +199,7 @@ func (repo *RepositoryImpl) GetUserByUsername(ctx context.Context, db *sql.DB, u
 }
 
 func (repo *RepositoryImpl) GetOrderByUsername(ctx context.Context, db *sql.DB, username string) ([]*domain.Orders, error) {
-	query := "SELECT id, product_id, product_name, username, quantity, total, status, created_at, modified_at FROM orders WHERE username = ?"
+	query := "SELECT " + orderColumns + " FROM orders WHERE username = ? ORDER BY created_at DESC"
 	result, err := db.QueryContext(ctx, query, username)
 	if err != nil {
 		logger.GetLogger("repository-log").Log("get orders by username", "error", err.Error())
@@ -219,20 +209,19 @@ func (repo *RepositoryImpl) GetOrderByUsername(ctx context.Context, db *sql.DB,
 	var rows []*domain.Orders
 	for result.Next() {
 		var row domain.Orders
-		if err := result.Scan(&row.Id, &row.ProductId, &row.ProductName, &row.Username, &row.Quantity, &row.Total, &row.Status, &row.CreatedAt, &row.ModifiedAt); err != nil {
+		if err := scanOrder(result, &row); err != nil {
 			logger.GetLogger("repository-log").Log("get orders by username", "error", err.Error())
 			return nil, err
 		}
 		rows = append(rows, &row)
 	}
-	return rows, nil
+	return rows, result.Err()
 }
 
 func (repo *RepositoryImpl) GetOrderById(ctx context.Context, db *sql.DB, id string) (*domain.Orders, error) {
-	query := "SELECT id, product_id, product_name, username, quantity, total, status, created_at, modified_at FROM orders WHERE id = ?"
-	result := db.QueryRowContext(ctx, query, id)
+	query := "SELECT " + orderColumns + " FROM orders WHERE id = ?"
 	var order domain.Orders
-	if err := result.Scan(&order.Id, &order.ProductId, &order.ProductName, &order.Username, &order.Quantity, &order.Total, &order.Status, &order.CreatedAt, &order.ModifiedAt); err != nil {
+	if err := scanOrder(db.QueryRowContext(ctx, query, id), &order); err != nil {
 		return nil, err
 	}
 	return &order, nil
@@ -271,8 +260,11 @@ func (repo *RepositoryImpl) GetLog(ctx context.Context) ([]*domain.Hit, error) {
 }
 
 func (repo *RepositoryImpl) AddOrders(ctx context.Context, tx *sql.Tx, orderDetails *domain.Orders, id uuid.UUID) error {
-	query := "INSERT INTO orders(id, product_id, product_name, name, phone, alamat, kecamatan, desa, username, quantity, total) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	_, err := tx.ExecContext(ctx, query, id, orderDetails.ProductId, orderDetails.ProductName, orderDetails.Name, orderDetails.Phone, orderDetails.Alamat, orderDetails.Kecamatan, orderDetails.Desa, orderDetails.Username, orderDetails.Quantity, orderDetails.Total)
+	query := "INSERT INTO orders(id, code, invoice_number, tracking_token, product_id, product_name, variant, name, phone, alamat, kecamatan, kecamatan_code, desa, desa_code, username, quantity, subtotal, discount, voucher_code, delivery_fee, total, payment_method, reserved_until, delivery_date, delivery_slot, customer_note) VALUES (?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, id, orderDetails.Code, orderDetails.InvoiceNumber, orderDetails.TrackingToken, orderDetails.ProductId, orderDetails.ProductName, orderDetails.Variant, orderDetails.Name, orderDetails.Phone, orderDetails.Alamat, orderDetails.Kecamatan, orderDetails.KecamatanCode, orderDetails.Desa, orderDetails.DesaCode, orderDetails.Username, orderDetails.Quantity, orderDetails.Subtotal, orderDetails.Discount, orderDetails.VoucherCode, orderDetails.DeliveryFee, orderDetails.Total, orderDetails.PaymentMethod, orderDetails.ReservedUntil, orderDetails.DeliveryDate, orderDetails.DeliverySlot, orderDetails.CustomerNote)
+	if isDuplicateKey(err, "uq_orders_code") {
+		return ErrOrderCodeTaken
+	}
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"khaira-admin/domain"
 	"khaira-admin/helper"
@@ -26,13 +27,15 @@ func TestGetProducts(t *testing.T) {
 			name: "Test GetProducts Success",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Id", "Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
+					"Id", "Name", "Description", "Stock", "Price", "Category", "ImageMetadata", "CreatedAt", "ModifiedAt",
 				}).AddRow(
 					id,
 					"Product 1",
 					"1st Product",
 					10,
 					1000,
+					"umum",
+					"{}",
 					now,
 					now,
 				)
@@ -42,13 +45,15 @@ func TestGetProducts(t *testing.T) {
 			expectedErr: false,
 			expectedResult: []*domain.Domain{
 				{
-					Id:          id,
-					Name:        "Product 1",
-					Description: "1st Product",
-					Stock:       10,
-					Price:       1000,
-					CreatedAt:   &now,
-					ModifiedAt:  &now,
+					Id:            id,
+					Name:          "Product 1",
+					Description:   "1st Product",
+					Stock:         10,
+					Price:         1000,
+					Category:      "umum",
+					ImageMetadata: "{}",
+					CreatedAt:     &now,
+					ModifiedAt:    &now,
 				},
 			},
 		},
@@ -56,8 +61,8 @@ func TestGetProducts(t *testing.T) {
 			name: "1 column missing",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
-				}).AddRow("Product 1", "1st Product", 10, 1000, now, now)
+					"Name", "Description", "Stock", "Price", "Category", "ImageMetadata", "CreatedAt", "ModifiedAt",
+				}).AddRow("Product 1", "1st Product", 10, 1000, "umum", nil, now, now)
 				mock.ExpectQuery("(?i)select \\* from products").WillReturnRows(rows)
 			},
 			expectedErr: true,
@@ -84,7 +89,7 @@ func TestGetProducts(t *testing.T) {
 			name: "empty result",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Id", "Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
+					"Id", "Name", "Description", "Stock", "Price", "Category", "ImageMetadata", "CreatedAt", "ModifiedAt",
 				})
 				mock.ExpectQuery("(?i)select .* from products").WillReturnRows(rows)
 			},
@@ -95,13 +100,15 @@ func TestGetProducts(t *testing.T) {
 			name: "scan error due to type mismatch",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := mock.NewRows([]string{
-					"Id", "Name", "Description", "Stock", "Price", "CreatedAt", "ModifiedAt",
+					"Id", "Name", "Description", "Stock", "Price", "Category", "ImageMetadata", "CreatedAt", "ModifiedAt",
 				}).AddRow(
 					"wrong-type", // should be UUID
 					123,          // should be string
 					"desc",
 					"invalid-int",
 					"invalid-float",
+					"umum",
+					nil,
 					time.Now(),
 					time.Now(),
 				)
@@ -138,11 +145,16 @@ func TestGetProducts(t *testing.T) {
 			}
 
 			assert.NoError(t, err)
+			if !assert.Len(t, result, len(tt.expectedResult)) {
+				return
+			}
 			assert.Equal(t, tt.expectedResult[0].Id, result[0].Id)
 			assert.Equal(t, tt.expectedResult[0].Name, result[0].Name)
 			assert.Equal(t, tt.expectedResult[0].Description, result[0].Description)
 			assert.Equal(t, tt.expectedResult[0].Stock, result[0].Stock)
 			assert.Equal(t, tt.expectedResult[0].Price, result[0].Price)
+			assert.Equal(t, tt.expectedResult[0].Category, result[0].Category)
+			assert.Equal(t, tt.expectedResult[0].ImageMetadata, result[0].ImageMetadata)
 			assert.WithinDuration(t, *tt.expectedResult[0].CreatedAt, *result[0].CreatedAt, time.Second)
 			assert.WithinDuration(t, *tt.expectedResult[0].ModifiedAt, *result[0].ModifiedAt, time.Second)
 		})
@@ -157,6 +169,7 @@ func TestAddProduct(t *testing.T) {
 	description := "1st Product"
 	stock := 100
 	price := 2000
+	category := "umum"
 
 	tests := []struct {
 		name           string
@@ -168,8 +181,8 @@ func TestAddProduct(t *testing.T) {
 			name: "Success",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("(?i)insert\\s+into\\s+products\\s*\\(\\s*id\\s*,\\s*name\\s*,\\s*description\\s*,\\s*stock\\s*,\\s*price\\s*,\\s*created_at\\s*\\)\\s*values\\s*\\(\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*\\)").
-					WithArgs(id, name, description, stock, price, created_at).
+				mock.ExpectExec(`(?i)^insert into products\(id, name, description, stock, price, category, image_metadata, created_at\) values\(\?, \?, \?, \?, \?, \?, \?, \?\)$`).
+					WithArgs(id, name, description, stock, price, category, "", created_at).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			expectedErr: false,
@@ -179,6 +192,7 @@ func TestAddProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				CreatedAt:   &created_at,
 			},
 		},
@@ -186,8 +200,8 @@ func TestAddProduct(t *testing.T) {
 			name: "1 column missing except description",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("(?i)insert\\s+into\\s+products\\s*\\(\\s*id\\s*,\\s*name\\s*,\\s*description\\s*,\\s*stock\\s*,\\s*price\\s*,\\s*created_at\\s*\\)\\s*values\\s*\\(\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*,\\s*\\?\\s*\\)").
-					WithArgs(id, "", description, stock, price, created_at).
+				mock.ExpectExec(`(?i)^insert into products\(id, name, description, stock, price, category, image_metadata, created_at\) values\(\?, \?, \?, \?, \?, \?, \?, \?\)$`).
+					WithArgs(id, "", description, stock, price, category, "", created_at).
 					WillReturnError(errors.New("field name cannot empty"))
 			},
 			expectedErr: true,
@@ -197,6 +211,7 @@ func TestAddProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				CreatedAt:   &created_at,
 			},
 		},
@@ -244,7 +259,9 @@ func TestAddProduct(t *testing.T) {
 				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
+				if !assert.NotNil(t, result) {
+					return
+				}
 				assert.Equal(t, tt.expectedResult.Id, result.Id)
 				assert.Equal(t, tt.expectedResult.Name, result.Name)
 				assert.Equal(t, tt.expectedResult.Description, result.Description)
@@ -265,6 +282,7 @@ func TestUpdateProduct(t *testing.T) {
 	description := "2nd Product"
 	stock := 10
 	price := 1000
+	category := "umum"
 
 	tests := []struct {
 		name           string
@@ -280,18 +298,19 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
-					WithArgs(name, description, stock, price, modified_at, id).
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*category\s*=\s*COALESCE\(NULLIF\(\?, ''\), category\),\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
+					WithArgs(name, description, stock, price, category, modified_at, id).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
-				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, created_at, modified_at from products where id = \?$`).
+				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, category, created_at, modified_at from products where id = \?$`).
 					WithArgs(id).
-					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "stock", "price", "created_at", "modified_at"}).
-						AddRow(id, name, description, stock, price, time.Now(), modified_at))
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "stock", "price", "category", "created_at", "modified_at"}).
+						AddRow(id, name, description, stock, price, category, time.Now(), modified_at))
 			},
 			expectedErr: false,
 			expectedResult: &domain.Domain{
@@ -300,6 +319,7 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 		},
@@ -310,12 +330,13 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
-					WithArgs(name, description, stock, price, modified_at, id).
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*category\s*=\s*COALESCE\(NULLIF\(\?, ''\), category\),\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
+					WithArgs(name, description, stock, price, category, modified_at, id).
 					WillReturnError(errors.New("1 column missing"))
 			},
 			expectedErr:    true,
@@ -328,12 +349,13 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
-					WithArgs(name, description, stock, price, modified_at, id).
+				mock.ExpectExec(`(?i)^update\s+products\s+set\s+name\s*=\s*\?,\s*description\s*=\s*\?,\s*stock\s*=\s*\?,\s*price\s*=\s*\?,\s*category\s*=\s*COALESCE\(NULLIF\(\?, ''\), category\),\s*modified_at\s*=\s*\?\s+where\s+id\s*=\s*\?\s*$`).
+					WithArgs(name, description, stock, price, category, modified_at, id).
 					WillReturnError(errors.New("failed to update product"))
 			},
 			expectedErr:    true,
@@ -346,6 +368,7 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -361,6 +384,7 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -376,12 +400,13 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(`(?i)^update\s+products`).
-					WithArgs(name, description, stock, price, modified_at, id).
+					WithArgs(name, description, stock, price, category, modified_at, id).
 					WillReturnResult(sqlmock.NewResult(0, 0)) // No rows affected
 			},
 			expectedErr:    true,
@@ -394,15 +419,16 @@ func TestUpdateProduct(t *testing.T) {
 				Description: description,
 				Stock:       stock,
 				Price:       price,
+				Category:    category,
 				ModifiedAt:  &modified_at,
 			},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(`(?i)^update\s+products`).
-					WithArgs(name, description, stock, price, modified_at, id).
+					WithArgs(name, description, stock, price, category, modified_at, id).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
-				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, created_at, modified_at from products where id = \?$`).
+				mock.ExpectQuery(`(?i)^select id, name, description, stock, price, category, created_at, modified_at from products where id = \?$`).
 					WithArgs(id).
 					WillReturnError(errors.New("select failed"))
 			},
@@ -452,61 +478,45 @@ func TestUpdateProduct(t *testing.T) {
 	}
 }
 
-func TestGetOrders(t *testing.T) {
-	createdAt := time.Now()
-	modifiedAt := time.Now()
+func TestUpdateOrder(t *testing.T) {
+	id := "1"
+	status := "done"
 
 	tests := []struct {
 		name           string
 		setupMock      func(mock sqlmock.Sqlmock)
 		expectedErr    bool
-		expectedResult []*domain.Orders
+		expectedResult *domain.Orders
 	}{
 		{
-			name: "success get orders",
+			name: "success update order",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{
-					"id", "product_id", "product_name", "username", "quantity", "total", "status", "created_at", "modified_at",
-				}).AddRow(
-					"1", "101", "ProductA", "user1", 2, 100.0, "pending", createdAt, modifiedAt,
-				)
-
-				mock.ExpectQuery("SELECT \\* FROM orders").WillReturnRows(rows)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status = \\? WHERE id = \\?").
+					WithArgs(status, id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
 			expectedErr: false,
-			expectedResult: []*domain.Orders{
-				{
-					Id:          "1",
-					ProductId:   "101",
-					ProductName: "ProductA",
-					Username:    "user1",
-					Quantity:    2,
-					Total:       100.0,
-					Status:      "pending",
-					CreatedAt:   &createdAt,
-					ModifiedAt:  &modifiedAt,
-				},
+			expectedResult: &domain.Orders{
+				Id:     id,
+				Status: status,
 			},
 		},
 		{
-			name: "order not found",
+			name: "update failed",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{
-					"id", "product_id", "product_name", "username", "quantity", "total", "status", "created_at", "modified_at",
-				})
-				mock.ExpectQuery("SELECT \\* FROM orders").WillReturnRows(rows)
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE orders SET status = \\? WHERE id = \\?").
+					WithArgs(status, id).
+					WillReturnError(errors.New("update error"))
 			},
 			expectedErr:    true,
 			expectedResult: nil,
 		},
 		{
-			name: "data corrupted on scan",
+			name: "transaction begin failed",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{
-					"id", "product_id", "product_name", "username", "quantity", "total", "status", "created_at", "modified_at",
-				}).AddRow("1", "101", "ProductA", "user1", "invalid", "total", "done", createdAt, modifiedAt)
-
-				mock.ExpectQuery("SELECT \\* FROM orders").WillReturnRows(rows)
+				mock.ExpectBegin().WillReturnError(errors.New("begin tx failed"))
 			},
 			expectedErr:    true,
 			expectedResult: nil,
@@ -525,24 +535,24 @@ func TestGetOrders(t *testing.T) {
 
 			tt.setupMock(mock)
 
+			tx, err := db.Begin()
+			if tt.expectedErr && tt.name == "transaction begin failed" {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
 			repo := NewRepositoryImpl(elastic)
-			result, err := repo.GetOrders(context.Background(), db)
+			order := &domain.Orders{
+				Id:     id,
+				Status: status,
+			}
+			err = repo.UpdateOrder(context.Background(), tx, order, id)
 
 			if tt.expectedErr {
-				assert.Nil(t, result)
+				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.NotNil(t, result)
-				assert.Equal(t, len(tt.expectedResult), len(result))
-				assert.Equal(t, tt.expectedResult[0].Id, result[0].Id)
-				assert.Equal(t, tt.expectedResult[0].ProductId, result[0].ProductId)
-				assert.Equal(t, tt.expectedResult[0].ProductName, result[0].ProductName)
-				assert.Equal(t, tt.expectedResult[0].Username, result[0].Username)
-				assert.Equal(t, tt.expectedResult[0].Quantity, result[0].Quantity)
-				assert.Equal(t, tt.expectedResult[0].Total, result[0].Total)
-				assert.Equal(t, tt.expectedResult[0].Status, result[0].Status)
-				assert.WithinDuration(t, *tt.expectedResult[0].CreatedAt, *result[0].CreatedAt, time.Second)
-				assert.WithinDuration(t, *tt.expectedResult[0].ModifiedAt, *result[0].ModifiedAt, time.Second)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -550,48 +560,88 @@ func TestGetOrders(t *testing.T) {
 	}
 }
 
-func TestUpdateOrder(t *testing.T) {
+func TestDeleteOrder(t *testing.T) {
 	id := "1"
-	status := "done"
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM orders WHERE id = \\? AND invoice_number IS NULL").
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, sqlmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+				return
+			}
+			repo := NewRepositoryImpl(elastic)
+			tt.setupMock(sqlmock)
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+				return
+			}
+
+			err = repo.DeleteOrder(context.Background(), tx, id)
+			assert.Nil(t, err)
+			sqlmock.ExpectationsWereMet()
+		})
+	}
+}
+
+func TestSumBookedPortions(t *testing.T) {
+	date, _ := domain.ParseDate("2026-10-20")
 
 	tests := []struct {
 		name           string
+		category       string
 		setupMock      func(mock sqlmock.Sqlmock)
 		expectedErr    bool
-		expectedResult *domain.Orders
+		expectedResult int
 	}{
 		{
-			name: "success update order",
+			name: "overall",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE orders SET status = \\? WHERE id = \\?").
-					WithArgs(status, id).
-					WillReturnResult(sqlmock.NewResult(0, 1))
-			},
-			expectedErr: false,
-			expectedResult: &domain.Orders{
-				Id:     id,
-				Status: status,
+				mock.ExpectQuery(`(?i)select coalesce\(sum\(o.quantity\), 0\) from orders o join products p on p.id = o.product_id where o.delivery_date = \? and o.status not in \(\?, \?, \?\)$`).
+					WithArgs("2026-10-20", domain.OrderStatusCancelled, domain.OrderStatusPartiallyRefunded, domain.OrderStatusRefunded).
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(120))
 			},
+			expectedResult: 120,
 		},
 		{
-			name: "update failed",
+			name:     "by category",
+			category: "nasi box",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("UPDATE orders SET status = \\? WHERE id = \\?").
-					WithArgs(status, id).
-					WillReturnError(errors.New("update error"))
+				mock.ExpectQuery(`(?i)select coalesce\(sum\(o.quantity\), 0\) from orders o .* and p.category = \?$`).
+					WithArgs("2026-10-20", domain.OrderStatusCancelled, domain.OrderStatusPartiallyRefunded, domain.OrderStatusRefunded, "nasi box").
+					WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(35))
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedResult: 35,
 		},
 		{
-			name: "transaction begin failed",
+			name: "query failed",
 			setupMock: func(mock sqlmock.Sqlmock) {
-				mock.ExpectBegin().WillReturnError(errors.New("begin tx failed"))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select coalesce`).WillReturnError(errors.New("connection lost"))
 			},
-			expectedErr:    true,
-			expectedResult: nil,
+			expectedErr: true,
 		},
 	}
 
@@ -608,23 +658,84 @@ func TestUpdateOrder(t *testing.T) {
 			tt.setupMock(mock)
 
 			tx, err := db.Begin()
-			if tt.expectedErr && tt.name == "transaction begin failed" {
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.SumBookedPortions(context.Background(), tx, date, tt.category)
+
+			if tt.expectedErr {
 				assert.Error(t, err)
-				return
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFindDeliveryZone(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult *domain.DeliveryZone
+	}{
+		{
+			name: "desa zone found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select id, kecamatan, desa, fee, min_order, deliverable from delivery_zones where kecamatan = \? and desa in \(\?, ''\) order by desa = '' limit 1`).
+					WithArgs("Cibinong", "Pakansari").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "kecamatan", "desa", "fee", "min_order", "deliverable"}).
+						AddRow("z1", "Cibinong", "Pakansari", 10000, 150000, true))
+			},
+			expectedResult: &domain.DeliveryZone{
+				Id:          "z1",
+				Kecamatan:   "Cibinong",
+				Desa:        "Pakansari",
+				Fee:         10000,
+				MinOrder:    150000,
+				Deliverable: true,
+			},
+		},
+		{
+			name: "no zone",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from delivery_zones`).
+					WithArgs("Cibinong", "Pakansari").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
 			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
 			assert.NoError(t, err)
 
 			repo := NewRepositoryImpl(elastic)
-			order := &domain.Orders{
-				Id:     id,
-				Status: status,
-			}
-			err = repo.UpdateOrder(context.Background(), tx, order, id)
+			result, err := repo.FindDeliveryZone(context.Background(), tx, "Cibinong", "Pakansari")
 
 			if tt.expectedErr {
 				assert.Error(t, err)
+				assert.Nil(t, result)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -632,46 +743,742 @@ func TestUpdateOrder(t *testing.T) {
 	}
 }
 
-func TestDeleteOrder(t *testing.T) {
-	id := "1"
+func TestUpdateDeliveryZone(t *testing.T) {
 	tests := []struct {
 		name        string
-		setupMock   func(sqlmock sqlmock.Sqlmock)
+		setupMock   func(mock sqlmock.Sqlmock)
 		expectedErr bool
 	}{
 		{
-			name: "Success",
+			name: "zone changed",
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
-				mock.ExpectExec("DELETE FROM orders WHERE id = \\?").
-					WithArgs(id).
+				mock.ExpectExec(`(?i)update delivery_zones set`).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 			},
-			expectedErr: false,
+		},
+		{
+			name: "saved without changes",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update delivery_zones set`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)select count\(\*\) from delivery_zones where id = \?`).
+					WithArgs("z1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name: "unknown zone",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update delivery_zones set`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)select count\(\*\) from delivery_zones where id = \?`).
+					WithArgs("z1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedErr: true,
 		},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			elastic, err := helper.NewElasticClient()
 			if err != nil {
 				t.Fatal(err)
 			}
-			db, sqlmock, err := sqlmock.New()
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl(elastic)
+			zone := &domain.DeliveryZone{Kecamatan: "Cibinong", Desa: "Pakansari", Fee: 10000, MinOrder: 150000, Deliverable: true}
+			err = repo.UpdateDeliveryZone(context.Background(), tx, zone, "z1")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLockVoucherByCode(t *testing.T) {
+	columns := []string{"id", "code", "description", "type", "value", "max_discount", "min_order", "usage_limit", "per_customer_limit", "used_count",
+		"product_ids", "categories", "valid_from", "valid_until", "active", "created_at", "modified_at"}
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult *domain.Voucher
+	}{
+		{
+			name: "voucher found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from vouchers where code = \? for update`).
+					WithArgs("HEMAT10").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("v1", "HEMAT10", "", "percentage", 10.0, 50000.0, 200000.0, 100, 1, 3, "P001,P002", "", nil, nil, true, nil, nil))
+			},
+			expectedResult: &domain.Voucher{
+				Id:               "v1",
+				Code:             "HEMAT10",
+				Type:             "percentage",
+				Value:            10,
+				MaxDiscount:      50000,
+				MinOrder:         200000,
+				UsageLimit:       100,
+				PerCustomerLimit: 1,
+				UsedCount:        3,
+				ProductIds:       []string{"P001", "P002"},
+				Categories:       []string{},
+				Active:           true,
+			},
+		},
+		{
+			name: "unknown code",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from vouchers`).
+					WithArgs("HEMAT10").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
 			if err != nil {
 				t.Fatal(err)
-				return
 			}
-			repo := NewRepositoryImpl(elastic)
-			tt.setupMock(sqlmock)
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
 			tx, err := db.Begin()
-			if err != nil {
-				t.Fatal(err)
-				return
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.LockVoucherByCode(context.Background(), tx, "HEMAT10")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
 			}
 
-			err = repo.DeleteOrder(context.Background(), tx, id)
-			assert.Nil(t, err)
-			sqlmock.ExpectationsWereMet()
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUpdateVoucher(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "voucher changed",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update vouchers set`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "saved without changes",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update vouchers set`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)select count\(\*\) from vouchers where id = \?`).
+					WithArgs("v1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name: "unknown voucher",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update vouchers set`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`(?i)select count\(\*\) from vouchers where id = \?`).
+					WithArgs("v1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl(elastic)
+			voucher := &domain.Voucher{Code: "HEMAT10", Type: domain.DiscountPercentage, Value: 10, ProductIds: []string{}, Categories: []string{}, Active: true}
+			err = repo.UpdateVoucher(context.Background(), tx, voucher, "v1")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestClaimIdempotencyKey(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	key := &domain.IdempotencyKey{
+		Key:            "retry-1",
+		Fingerprint:    "abc",
+		ResponseStatus: 204,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(24 * time.Hour),
+	}
+	tests := []struct {
+		name            string
+		setupMock       func(mock sqlmock.Sqlmock)
+		expectedErr     bool
+		expectedClaimed bool
+	}{
+		{
+			name: "fresh key",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)delete from idempotency_keys where idempotency_key = \? and expires_at <= \?`).
+					WithArgs("retry-1", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`(?i)insert ignore into idempotency_keys`).
+					WithArgs("retry-1", "abc", 204, now, now.Add(24*time.Hour)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedClaimed: true,
+		},
+		{
+			name: "key already taken",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)delete from idempotency_keys`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`(?i)insert ignore into idempotency_keys`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "insert fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)delete from idempotency_keys`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`(?i)insert ignore into idempotency_keys`).
+					WillReturnError(errors.New("db down"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl(elastic)
+			claimed, err := repo.ClaimIdempotencyKey(context.Background(), tx, key)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedClaimed, claimed)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeductStock(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "enough stock",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update products set stock = stock - \? where id = \? and stock >= \?`).
+					WithArgs(5, "P001", 5).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "not enough stock",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`(?i)update products set stock`).
+					WithArgs(5, "P001", 5).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			repo := NewRepositoryImpl(elastic)
+			err = repo.DeductStock(context.Background(), tx, "P001", 5)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearchOrders(t *testing.T) {
+	minTotal := 100000.0
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		filter      *domain.OrderFilter
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "filters and keyset",
+			filter: &domain.OrderFilter{
+				Statuses:   []string{"pending", "confirmed"},
+				Kecamatan:  "Cibinong",
+				MinTotal:   &minTotal,
+				Query:      "siti",
+				SortBy:     domain.OrderSortCreatedAt,
+				Descending: true,
+				Limit:      51,
+				After:      &domain.OrderCursor{Value: createdAt, Id: "o-9"},
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders where status in \(\?, \?\) and \(kecamatan = \? or kecamatan_code = \?\) and total >= \? and \(name like \? or alamat like \?\) and \(created_at < \? or \(created_at = \? and id < \?\)\) order by created_at desc, id desc limit \?`).
+					WithArgs("pending", "confirmed", "Cibinong", "Cibinong", minTotal, "%siti%", "%siti%", createdAt, createdAt, "o-9", 51).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name:   "order code",
+			filter: &domain.OrderFilter{Query: "kh-7q3f9", SortBy: domain.OrderSortCreatedAt, Limit: 10},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders where \(code = \? or name like \? or alamat like \?\) order by created_at asc, id asc limit \?`).
+					WithArgs("KH-7Q3F9", "%kh-7q3f9%", "%kh-7q3f9%", 10).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name:   "query fails",
+			filter: &domain.OrderFilter{SortBy: domain.OrderSortTotal, Limit: 10},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders order by total asc, id asc limit \?`).
+					WithArgs(10).
+					WillReturnError(errors.New("db down"))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.SearchOrders(context.Background(), db, tt.filter)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Empty(t, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrderById(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	columns := []string{"id", "code", "invoice_number", "product_id", "product_name", "variant", "username", "name", "phone", "alamat", "kecamatan", "kecamatan_code",
+		"desa", "desa_code", "quantity", "subtotal", "discount", "voucher_code", "delivery_fee", "total", "amount_paid", "payment_method", "reserved_until",
+		"status", "delivery_date", "delivery_slot", "courier", "customer_note", "created_at", "modified_at"}
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult *domain.Orders
+	}{
+		{
+			name: "order found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select id, .*name, phone, alamat, kecamatan, .* from orders where id = \?`).
+					WithArgs("o-1").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("o-1", "KH-7Q3F9", "INV/2026/10/0001", "P001", "Nasi Box", "", "user1", "Siti", "081234567890",
+						"Jl. Mawar 1", "Cibinong", "3201010", "Pakansari", "3201010001", 10, 250000.0, 0.0, "", 15000.0, 265000.0, 100000.0, "transfer", nil,
+						"confirmed", nil, "siang", "Budi", "pagar hijau", createdAt, createdAt))
+			},
+			expectedResult: &domain.Orders{
+				Id:            "o-1",
+				Code:          "KH-7Q3F9",
+				InvoiceNumber: "INV/2026/10/0001",
+				ProductId:     "P001",
+				ProductName:   "Nasi Box",
+				Username:      "user1",
+				Name:          "Siti",
+				Phone:         "081234567890",
+				Alamat:        "Jl. Mawar 1",
+				Kecamatan:     "Cibinong",
+				KecamatanCode: "3201010",
+				Desa:          "Pakansari",
+				DesaCode:      "3201010001",
+				Quantity:      10,
+				Subtotal:      250000,
+				DeliveryFee:   15000,
+				Total:         265000,
+				AmountPaid:    100000,
+				PaymentState:  domain.PaymentStatePartial,
+				PaymentMethod: "transfer",
+				Status:        "confirmed",
+				DeliverySlot:  "siang",
+				Courier:       "Budi",
+				CustomerNote:  "pagar hijau",
+				CreatedAt:     &createdAt,
+				ModifiedAt:    &createdAt,
+			},
+		},
+		{
+			name: "order not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders where id = \?`).
+					WithArgs("o-1").
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.GetOrderById(context.Background(), db, "o-1")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetOrderByTrackingToken(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	token := "Zq0mH4m3Yx2rQ8vV1kP7sT9wN6bD5fG3hJ2lC0aE4uI"
+	columns := []string{"id", "code", "invoice_number", "product_id", "product_name", "variant", "username", "name", "phone", "alamat", "kecamatan", "kecamatan_code",
+		"desa", "desa_code", "quantity", "subtotal", "discount", "voucher_code", "delivery_fee", "total", "amount_paid", "payment_method", "reserved_until",
+		"status", "delivery_date", "delivery_slot", "courier", "customer_note", "created_at", "modified_at"}
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    error
+		expectedResult *domain.Orders
+	}{
+		{
+			name: "order found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select id, .* from orders where tracking_token = \?`).
+					WithArgs(token).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("o-1", "KH-7Q3F9", "INV/2026/10/0001", "P001", "Nasi Box", "", "user1", "Siti", "+6281234567890",
+						"Jl. Mawar 1", "Cibinong", "3201010", "Pakansari", "3201010001", 10, 250000.0, 0.0, "", 15000.0, 265000.0, 265000.0, "transfer", nil,
+						"delivering", nil, "siang", "Budi", "", createdAt, createdAt))
+			},
+			expectedResult: &domain.Orders{
+				Id:            "o-1",
+				Code:          "KH-7Q3F9",
+				InvoiceNumber: "INV/2026/10/0001",
+				TrackingToken: token,
+				ProductId:     "P001",
+				ProductName:   "Nasi Box",
+				Username:      "user1",
+				Name:          "Siti",
+				Phone:         "+6281234567890",
+				Alamat:        "Jl. Mawar 1",
+				Kecamatan:     "Cibinong",
+				KecamatanCode: "3201010",
+				Desa:          "Pakansari",
+				DesaCode:      "3201010001",
+				Quantity:      10,
+				Subtotal:      250000,
+				DeliveryFee:   15000,
+				Total:         265000,
+				AmountPaid:    265000,
+				PaymentState:  domain.PaymentStatePaid,
+				PaymentMethod: "transfer",
+				Status:        "delivering",
+				DeliverySlot:  "siang",
+				Courier:       "Budi",
+				CreatedAt:     &createdAt,
+				ModifiedAt:    &createdAt,
+			},
+		},
+		{
+			name: "unknown token",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders where tracking_token = \?`).
+					WithArgs(token).
+					WillReturnError(sql.ErrNoRows)
+			},
+			expectedErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.GetOrderByTrackingToken(context.Background(), db, token)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProductRatings(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult []*domain.ProductRating
+	}{
+		{
+			name: "approved ratings per product",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select product_id, avg\(product_rating\), count\(\*\) from feedback where status = \? group by product_id`).
+					WithArgs(domain.FeedbackStatusApproved).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "avg", "count"}).
+						AddRow("P001", 4.5, 2).
+						AddRow("P002", 3.0, 1))
+			},
+			expectedResult: []*domain.ProductRating{
+				{ProductId: "P001", AverageRating: 4.5, RatingCount: 2},
+				{ProductId: "P002", AverageRating: 3, RatingCount: 1},
+			},
+		},
+		{
+			name: "no approved feedback yet",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from feedback`).
+					WithArgs(domain.FeedbackStatusApproved).
+					WillReturnRows(sqlmock.NewRows([]string{"product_id", "avg", "count"}))
+			},
+		},
+		{
+			name: "query fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from feedback`).
+					WithArgs(domain.FeedbackStatusApproved).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.GetProductRatings(context.Background(), db)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProductionItems(t *testing.T) {
+	date, _ := domain.ParseDate("2026-03-05")
+	columns := []string{"product_id", "name", "category", "variant", "id", "code", "invoice_number", "order_name", "quantity", "status", "customer_note"}
+
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedResult []*domain.ProductionItem
+	}{
+		{
+			name: "orders grouped by product and variant",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select o.product_id, p.name, .* order by p.category, p.name, o.product_id, o.variant, o.created_at$`).
+					WithArgs(date, "confirmed", "processing").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("P001", "Nasi Box", "katering", "", "o1", "KH-0001", "INV/2026/03/0001", "Siti", 10, "confirmed", "").
+						AddRow("P001", "Nasi Box", "katering", "", "o2", "KH-0002", "", "Budi", 5, "processing", "tanpa sambal").
+						AddRow("P001", "Nasi Box", "katering", "pedas", "o3", "", "", "Ani", 3, "confirmed", ""))
+			},
+			expectedResult: []*domain.ProductionItem{
+				{ProductId: "P001", ProductName: "Nasi Box", Category: "katering", Quantity: 15, Orders: []*domain.ProductionOrder{
+					{OrderId: "o1", Code: "KH-0001", InvoiceNumber: "INV/2026/03/0001", Name: "Siti", Quantity: 10, Status: "confirmed"},
+					{OrderId: "o2", Code: "KH-0002", Name: "Budi", Quantity: 5, Status: "processing", CustomerNote: "tanpa sambal"},
+				}},
+				{ProductId: "P001", ProductName: "Nasi Box", Category: "katering", Variant: "pedas", Quantity: 3, Orders: []*domain.ProductionOrder{
+					{OrderId: "o3", Name: "Ani", Quantity: 3, Status: "confirmed"},
+				}},
+			},
+		},
+		{
+			name: "query fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders o join products p`).
+					WithArgs(date, "confirmed", "processing").
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			elastic, err := helper.NewElasticClient()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			repo := NewRepositoryImpl(elastic)
+			result, err := repo.GetProductionItems(context.Background(), db, date, []string{"confirmed", "processing"})
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"time"
+)
+
+func (repo *RepositoryImpl) GetReservationPolicies(ctx context.Context, db *sql.DB) ([]*domain.ReservationPolicy, error) {
+	query := "SELECT payment_method, ttl_minutes FROM reservation_policies ORDER BY payment_method"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get reservation policies", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var policies []*domain.ReservationPolicy
+	for rows.Next() {
+		var policy domain.ReservationPolicy
+		if err := rows.Scan(&policy.PaymentMethod, &policy.TTLMinutes); err != nil {
+			logger.GetLogger("repository-log").Log("get reservation policies", "error", err.Error())
+			return nil, err
+		}
+		policies = append(policies, &policy)
+	}
+	return policies, rows.Err()
+}
+
+func (repo *RepositoryImpl) SaveReservationPolicy(ctx context.Context, tx *sql.Tx, entity *domain.ReservationPolicy) error {
+	query := "INSERT INTO reservation_policies(payment_method, ttl_minutes) VALUES (?, ?) ON DUPLICATE KEY UPDATE ttl_minutes = VALUES(ttl_minutes)"
+	if _, err := tx.ExecContext(ctx, query, entity.PaymentMethod, entity.TTLMinutes); err != nil {
+		logger.GetLogger("repository-log").Log("save reservation policy", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetReservationTTL(ctx context.Context, tx *sql.Tx, paymentMethod string) (int, error) {
+	query := "SELECT ttl_minutes FROM reservation_policies WHERE payment_method = ?"
+	var ttl int
+	if err := tx.QueryRowContext(ctx, query, paymentMethod).Scan(&ttl); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// GetExpiredReservations pages through pending orders whose reservation ran
+// out, ordered by id and starting after the given id, so callers can move
+// past orders they failed to release.
+func (repo *RepositoryImpl) GetExpiredReservations(ctx context.Context, db *sql.DB, now time.Time, after string, limit int) ([]string, error) {
+	query := "SELECT id FROM orders WHERE status = ? AND reserved_until < ? AND id > ? ORDER BY id LIMIT ?"
+	rows, err := db.QueryContext(ctx, query, domain.OrderStatusPending, now, after, limit)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get expired reservations", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			logger.GetLogger("repository-log").Log("get expired reservations", "error", err.Error())
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (repo *RepositoryImpl) ClearReservation(ctx context.Context, tx *sql.Tx, orderId string) error {
+	query := "UPDATE orders SET reserved_until = NULL WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, orderId); err != nil {
+		logger.GetLogger("repository-log").Log("clear reservation", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) RestoreStock(ctx context.Context, tx *sql.Tx, productId string, quantity int) error {
+	query := "UPDATE products SET stock = stock + ? WHERE id = ?"
+	if _, err := tx.ExecContext(ctx, query, quantity, productId); err != nil {
+		logger.GetLogger("repository-log").Log("restore stock", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) ReleaseVoucherRedemption(ctx context.Context, tx *sql.Tx, orderId string) error {
+	query := "UPDATE vouchers v JOIN voucher_redemptions r ON r.voucher_id = v.id SET v.used_count = v.used_count - 1 WHERE r.order_id = ?"
+	if _, err := tx.ExecContext(ctx, query, orderId); err != nil {
+		logger.GetLogger("repository-log").Log("release voucher redemption", "error", err.Error())
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM voucher_redemptions WHERE order_id = ?", orderId); err != nil {
+		logger.GetLogger("repository-log").Log("release voucher redemption", "error", err.Error())
+		return err
+	}
+	return nil
+}
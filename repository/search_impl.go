@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"strings"
+)
+
+// orderSortColumns maps the sort options to SQL expressions. Orders without a
+// delivery date sort before every dated order.
+var orderSortColumns = map[string]string{
+	domain.OrderSortCreatedAt:    "created_at",
+	domain.OrderSortDeliveryDate: "COALESCE(delivery_date, '1000-01-01')",
+	domain.OrderSortTotal:        "total",
+}
+
+func orderFilterClause(filter *domain.OrderFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "status IN ("+inPlaceholders(len(filter.Statuses))+")")
+		for _, status := range filter.Statuses {
+			args = append(args, status)
+		}
+	}
+	if filter.CreatedFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.CreatedTo)
+	}
+	if filter.DeliveryFrom != nil {
+		conditions = append(conditions, "delivery_date >= ?")
+		args = append(args, *filter.DeliveryFrom)
+	}
+	if filter.DeliveryTo != nil {
+		conditions = append(conditions, "delivery_date <= ?")
+		args = append(args, *filter.DeliveryTo)
+	}
+	if filter.Kecamatan != "" {
+		conditions = append(conditions, "(kecamatan = ? OR kecamatan_code = ?)")
+		args = append(args, filter.Kecamatan, filter.Kecamatan)
+	}
+	if filter.Desa != "" {
+		conditions = append(conditions, "(desa = ? OR desa_code = ?)")
+		args = append(args, filter.Desa, filter.Desa)
+	}
+	if filter.Username != "" {
+		conditions = append(conditions, "username = ?")
+		args = append(args, filter.Username)
+	}
+	if filter.Phone != "" {
+		conditions = append(conditions, "phone LIKE ?")
+		args = append(args, "%"+filter.Phone+"%")
+	}
+	if filter.ProductId != "" {
+		conditions = append(conditions, "product_id = ?")
+		args = append(args, filter.ProductId)
+	}
+	if filter.MinTotal != nil {
+		conditions = append(conditions, "total >= ?")
+		args = append(args, *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		conditions = append(conditions, "total <= ?")
+		args = append(args, *filter.MaxTotal)
+	}
+	if code, ok := domain.ParseOrderCode(filter.Query); ok {
+		conditions = append(conditions, "(code = ? OR name LIKE ? OR alamat LIKE ?)")
+		args = append(args, code, "%"+filter.Query+"%", "%"+filter.Query+"%")
+	} else if filter.Query != "" {
+		conditions = append(conditions, "(name LIKE ? OR alamat LIKE ?)")
+		args = append(args, "%"+filter.Query+"%", "%"+filter.Query+"%")
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// SearchOrders returns up to filter.Limit orders after filter.After in the
+// requested order. Pagination is keyset based, so pages stay stable while new
+// orders come in.
+func (repo *RepositoryImpl) SearchOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) ([]*domain.Orders, error) {
+	column, ok := orderSortColumns[filter.SortBy]
+	if !ok {
+		column = orderSortColumns[domain.OrderSortCreatedAt]
+	}
+	compare := ">"
+	if filter.Descending {
+		compare = "<"
+	}
+
+	where, args := orderFilterClause(filter)
+	if filter.After != nil {
+		keyset := "(" + column + " " + compare + " ? OR (" + column + " = ? AND id " + compare + " ?))"
+		if where == "" {
+			where = " WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+		args = append(args, filter.After.Value, filter.After.Value, filter.After.Id)
+	}
+	query := "SELECT " + orderColumns + " FROM orders" + where + orderSortClause(filter) + " LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("search orders", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	orders := []*domain.Orders{}
+	for rows.Next() {
+		var order domain.Orders
+		if err := scanOrder(rows, &order); err != nil {
+			logger.GetLogger("repository-log").Log("search orders", "error", err.Error())
+			return nil, err
+		}
+		orders = append(orders, &order)
+	}
+	return orders, rows.Err()
+}
+
+func (repo *RepositoryImpl) CountOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter) (int, error) {
+	where, args := orderFilterClause(filter)
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM orders"+where, args...).Scan(&total); err != nil {
+		logger.GetLogger("repository-log").Log("count orders", "error", err.Error())
+		return 0, err
+	}
+	return total, nil
+}
+
+func orderSortClause(filter *domain.OrderFilter) string {
+	column, ok := orderSortColumns[filter.SortBy]
+	if !ok {
+		column = orderSortColumns[domain.OrderSortCreatedAt]
+	}
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	return " ORDER BY " + column + " " + direction + ", id " + direction
+}
+
+// StreamOrders calls fn for every order matching the filter, one row at a
+// time, so exports do not have to load the whole result. Limit and cursor are
+// ignored.
+func (repo *RepositoryImpl) StreamOrders(ctx context.Context, db *sql.DB, filter *domain.OrderFilter, fn func(*domain.Orders) error) error {
+	where, args := orderFilterClause(filter)
+	rows, err := db.QueryContext(ctx, "SELECT "+orderColumns+" FROM orders"+where+orderSortClause(filter), args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("stream orders", "error", err.Error())
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var order domain.Orders
+		if err := scanOrder(rows, &order); err != nil {
+			logger.GetLogger("repository-log").Log("stream orders", "error", err.Error())
+			return err
+		}
+		if err := fn(&order); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
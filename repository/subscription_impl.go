@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"strconv"
+	"strings"
+)
+
+const subscriptionColumns = "id, username, name, phone, alamat, kecamatan, desa, product_id, variant, quantity, delivery_slot, days_of_week, start_date, end_date, status, paused_until, created_at, modified_at"
+
+func scanSubscription(row interface{ Scan(...any) error }, subscription *domain.Subscription) error {
+	var days string
+	err := row.Scan(&subscription.Id, &subscription.Username, &subscription.Name, &subscription.Phone, &subscription.Alamat,
+		&subscription.Kecamatan, &subscription.Desa, &subscription.ProductId, &subscription.Variant, &subscription.Quantity,
+		&subscription.DeliverySlot, &days, &subscription.StartDate, &subscription.EndDate, &subscription.Status,
+		&subscription.PausedUntil, &subscription.CreatedAt, &subscription.ModifiedAt)
+	if err != nil {
+		return err
+	}
+	subscription.DaysOfWeek = nil
+	for _, day := range splitList(days) {
+		value, err := strconv.Atoi(day)
+		if err != nil {
+			return err
+		}
+		subscription.DaysOfWeek = append(subscription.DaysOfWeek, value)
+	}
+	return nil
+}
+
+func joinDays(days []int) string {
+	values := make([]string, len(days))
+	for i, day := range days {
+		values[i] = strconv.Itoa(day)
+	}
+	return strings.Join(values, ",")
+}
+
+func (repo *RepositoryImpl) AddSubscription(ctx context.Context, tx *sql.Tx, entity *domain.Subscription) error {
+	query := "INSERT INTO subscriptions(id, username, name, phone, alamat, kecamatan, desa, product_id, variant, quantity, delivery_slot, days_of_week, start_date, end_date, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Username, entity.Name, entity.Phone, entity.Alamat, entity.Kecamatan, entity.Desa,
+		entity.ProductId, entity.Variant, entity.Quantity, entity.DeliverySlot, joinDays(entity.DaysOfWeek), entity.StartDate, entity.EndDate, entity.Status)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add subscription", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetSubscriptions(ctx context.Context, db *sql.DB, status string) ([]*domain.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE ? = '' OR status = ? ORDER BY created_at DESC"
+	return repo.querySubscriptions(ctx, db, query, status, status)
+}
+
+// GetSchedulableSubscriptions returns subscriptions that may deliver between
+// from and to. Paused ones are included because a pause can end in range.
+func (repo *RepositoryImpl) GetSchedulableSubscriptions(ctx context.Context, db *sql.DB, from domain.Date, to domain.Date) ([]*domain.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE status IN (?, ?) AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)"
+	return repo.querySubscriptions(ctx, db, query, domain.SubscriptionStatusActive, domain.SubscriptionStatusPaused, to, from)
+}
+
+func (repo *RepositoryImpl) querySubscriptions(ctx context.Context, db *sql.DB, query string, args ...any) ([]*domain.Subscription, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get subscriptions", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var subscriptions []*domain.Subscription
+	for rows.Next() {
+		var subscription domain.Subscription
+		if err := scanSubscription(rows, &subscription); err != nil {
+			logger.GetLogger("repository-log").Log("get subscriptions", "error", err.Error())
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+func (repo *RepositoryImpl) GetSubscription(ctx context.Context, db *sql.DB, id string) (*domain.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE id = ?"
+	var subscription domain.Subscription
+	if err := scanSubscription(db.QueryRowContext(ctx, query, id), &subscription); err != nil {
+		logger.GetLogger("repository-log").Log("get subscription", "error", err.Error())
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (repo *RepositoryImpl) LockSubscription(ctx context.Context, tx *sql.Tx, id string) (*domain.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE id = ? FOR UPDATE"
+	var subscription domain.Subscription
+	if err := scanSubscription(tx.QueryRowContext(ctx, query, id), &subscription); err != nil {
+		logger.GetLogger("repository-log").Log("lock subscription", "error", err.Error())
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (repo *RepositoryImpl) UpdateSubscriptionStatus(ctx context.Context, tx *sql.Tx, id string, status string, pausedUntil *domain.Date) error {
+	query := "UPDATE subscriptions SET status = ?, paused_until = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, status, pausedUntil, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update subscription status", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("subscription not found or status unchanged")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetSubscriptionSkips(ctx context.Context, db *sql.DB, id string) ([]domain.Date, error) {
+	query := "SELECT date FROM subscription_skips WHERE subscription_id = ? ORDER BY date"
+	rows, err := db.QueryContext(ctx, query, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get subscription skips", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	dates := []domain.Date{}
+	for rows.Next() {
+		var date domain.Date
+		if err := rows.Scan(&date); err != nil {
+			logger.GetLogger("repository-log").Log("get subscription skips", "error", err.Error())
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+	return dates, rows.Err()
+}
+
+func (repo *RepositoryImpl) AddSubscriptionSkip(ctx context.Context, tx *sql.Tx, id string, date domain.Date) error {
+	query := "INSERT IGNORE INTO subscription_skips(subscription_id, date) VALUES (?, ?)"
+	if _, err := tx.ExecContext(ctx, query, id, date); err != nil {
+		logger.GetLogger("repository-log").Log("add subscription skip", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) DeleteSubscriptionSkip(ctx context.Context, tx *sql.Tx, id string, date domain.Date) error {
+	query := "DELETE FROM subscription_skips WHERE subscription_id = ? AND date = ?"
+	result, err := tx.ExecContext(ctx, query, id, date)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete subscription skip", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("skip date not found")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) GetSubscriptionRuns(ctx context.Context, db *sql.DB, id string) ([]*domain.SubscriptionRun, error) {
+	query := "SELECT delivery_date, COALESCE(order_id, ''), error, modified_at FROM subscription_runs WHERE subscription_id = ? ORDER BY delivery_date DESC LIMIT 60"
+	rows, err := db.QueryContext(ctx, query, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get subscription runs", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []*domain.SubscriptionRun
+	for rows.Next() {
+		var run domain.SubscriptionRun
+		if err := rows.Scan(&run.DeliveryDate, &run.OrderId, &run.Error, &run.ModifiedAt); err != nil {
+			logger.GetLogger("repository-log").Log("get subscription runs", "error", err.Error())
+			return nil, err
+		}
+		runs = append(runs, &run)
+	}
+	return runs, rows.Err()
+}
+
+// ClaimSubscriptionRun makes sure a run row exists for the date and locks
+// it. It returns the order already created for that date, if any.
+func (repo *RepositoryImpl) ClaimSubscriptionRun(ctx context.Context, tx *sql.Tx, id string, date domain.Date) (string, error) {
+	insert := "INSERT IGNORE INTO subscription_runs(subscription_id, delivery_date) VALUES (?, ?)"
+	if _, err := tx.ExecContext(ctx, insert, id, date); err != nil {
+		logger.GetLogger("repository-log").Log("claim subscription run", "error", err.Error())
+		return "", err
+	}
+	query := "SELECT COALESCE(order_id, '') FROM subscription_runs WHERE subscription_id = ? AND delivery_date = ? FOR UPDATE"
+	var orderId string
+	if err := tx.QueryRowContext(ctx, query, id, date).Scan(&orderId); err != nil {
+		logger.GetLogger("repository-log").Log("claim subscription run", "error", err.Error())
+		return "", err
+	}
+	return orderId, nil
+}
+
+func (repo *RepositoryImpl) CompleteSubscriptionRun(ctx context.Context, tx *sql.Tx, id string, date domain.Date, orderId string) error {
+	query := "UPDATE subscription_runs SET order_id = ?, error = '' WHERE subscription_id = ? AND delivery_date = ?"
+	if _, err := tx.ExecContext(ctx, query, orderId, id, date); err != nil {
+		logger.GetLogger("repository-log").Log("complete subscription run", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) FailSubscriptionRun(ctx context.Context, db *sql.DB, id string, date domain.Date, reason string) error {
+	query := "INSERT INTO subscription_runs(subscription_id, delivery_date, error) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE error = VALUES(error)"
+	if _, err := db.ExecContext(ctx, query, id, date, reason); err != nil {
+		logger.GetLogger("repository-log").Log("fail subscription run", "error", err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (repo *RepositoryImpl) GetOrderByTrackingToken(ctx context.Context, db *sql.DB, token string) (*domain.Orders, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE tracking_token = ?"
+	var order domain.Orders
+	if err := scanOrder(db.QueryRowContext(ctx, query, token), &order); err != nil {
+		return nil, err
+	}
+	order.TrackingToken = token
+	return &order, nil
+}
+
+func (repo *RepositoryImpl) GetTrackingToken(ctx context.Context, db *sql.DB, orderId string) (string, error) {
+	query := "SELECT COALESCE(tracking_token, '') FROM orders WHERE id = ?"
+	var token string
+	if err := db.QueryRowContext(ctx, query, orderId).Scan(&token); err != nil {
+		logger.GetLogger("repository-log").Log("get tracking token", "error", err.Error())
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"strings"
+	"time"
+)
+
+const voucherColumns = "id, code, description, type, value, max_discount, min_order, usage_limit, per_customer_limit, used_count, product_ids, categories, valid_from, valid_until, active, created_at, modified_at"
+
+func scanVoucher(row interface{ Scan(...any) error }, voucher *domain.Voucher) error {
+	var productIds, categories string
+	err := row.Scan(&voucher.Id, &voucher.Code, &voucher.Description, &voucher.Type, &voucher.Value, &voucher.MaxDiscount, &voucher.MinOrder,
+		&voucher.UsageLimit, &voucher.PerCustomerLimit, &voucher.UsedCount, &productIds, &categories,
+		&voucher.ValidFrom, &voucher.ValidUntil, &voucher.Active, &voucher.CreatedAt, &voucher.ModifiedAt)
+	if err != nil {
+		return err
+	}
+	voucher.ProductIds = splitList(productIds)
+	voucher.Categories = splitList(categories)
+	return nil
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+func (repo *RepositoryImpl) GetVouchers(ctx context.Context, db *sql.DB) ([]*domain.Voucher, error) {
+	query := "SELECT " + voucherColumns + " FROM vouchers ORDER BY created_at DESC"
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get vouchers", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var vouchers []*domain.Voucher
+	for rows.Next() {
+		var voucher domain.Voucher
+		if err := scanVoucher(rows, &voucher); err != nil {
+			logger.GetLogger("repository-log").Log("get vouchers", "error", err.Error())
+			return nil, err
+		}
+		vouchers = append(vouchers, &voucher)
+	}
+	return vouchers, rows.Err()
+}
+
+func (repo *RepositoryImpl) LockVoucherByCode(ctx context.Context, tx *sql.Tx, code string) (*domain.Voucher, error) {
+	query := "SELECT " + voucherColumns + " FROM vouchers WHERE code = ? FOR UPDATE"
+	var voucher domain.Voucher
+	if err := scanVoucher(tx.QueryRowContext(ctx, query, code), &voucher); err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (repo *RepositoryImpl) AddVoucher(ctx context.Context, tx *sql.Tx, entity *domain.Voucher) error {
+	query := "INSERT INTO vouchers(id, code, description, type, value, max_discount, min_order, usage_limit, per_customer_limit, product_ids, categories, valid_from, valid_until, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Code, entity.Description, entity.Type, entity.Value, entity.MaxDiscount, entity.MinOrder,
+		entity.UsageLimit, entity.PerCustomerLimit, strings.Join(entity.ProductIds, ","), strings.Join(entity.Categories, ","),
+		entity.ValidFrom, entity.ValidUntil, entity.Active)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add voucher", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateVoucher(ctx context.Context, tx *sql.Tx, entity *domain.Voucher, id string) error {
+	query := "UPDATE vouchers SET code = ?, description = ?, type = ?, value = ?, max_discount = ?, min_order = ?, usage_limit = ?, per_customer_limit = ?, product_ids = ?, categories = ?, valid_from = ?, valid_until = ?, active = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Code, entity.Description, entity.Type, entity.Value, entity.MaxDiscount, entity.MinOrder,
+		entity.UsageLimit, entity.PerCustomerLimit, strings.Join(entity.ProductIds, ","), strings.Join(entity.Categories, ","),
+		entity.ValidFrom, entity.ValidUntil, entity.Active, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update voucher", "error", err.Error())
+		return err
+	}
+	return repo.checkUpdated(ctx, tx, result, "vouchers", id, "voucher not found")
+}
+
+func (repo *RepositoryImpl) DeactivateVoucher(ctx context.Context, tx *sql.Tx, id string) error {
+	query := "UPDATE vouchers SET active = FALSE WHERE id = ? AND active = TRUE"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("deactivate voucher", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("voucher not found or already inactive")
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) CountVoucherRedemptions(ctx context.Context, tx *sql.Tx, voucherId string, username string) (int, error) {
+	query := "SELECT COUNT(*) FROM voucher_redemptions WHERE voucher_id = ? AND username = ?"
+	var count int
+	if err := tx.QueryRowContext(ctx, query, voucherId, username).Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("count voucher redemptions", "error", err.Error())
+		return 0, err
+	}
+	return count, nil
+}
+
+func (repo *RepositoryImpl) RedeemVoucher(ctx context.Context, tx *sql.Tx, voucherId string, orderId string, username string, discount float64) error {
+	query := "INSERT INTO voucher_redemptions(voucher_id, order_id, username, discount) VALUES (?, ?, ?, ?)"
+	if _, err := tx.ExecContext(ctx, query, voucherId, orderId, username, discount); err != nil {
+		logger.GetLogger("repository-log").Log("redeem voucher", "error", err.Error())
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE vouchers SET used_count = used_count + 1 WHERE id = ?", voucherId); err != nil {
+		logger.GetLogger("repository-log").Log("redeem voucher", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+const discountRuleColumns = "id, name, min_quantity, type, value, product_id, category, valid_from, valid_until, active, created_at, modified_at"
+
+func scanDiscountRule(row interface{ Scan(...any) error }, rule *domain.DiscountRule) error {
+	return row.Scan(&rule.Id, &rule.Name, &rule.MinQuantity, &rule.Type, &rule.Value, &rule.ProductId, &rule.Category,
+		&rule.ValidFrom, &rule.ValidUntil, &rule.Active, &rule.CreatedAt, &rule.ModifiedAt)
+}
+
+func (repo *RepositoryImpl) GetDiscountRules(ctx context.Context, db *sql.DB) ([]*domain.DiscountRule, error) {
+	return repo.queryDiscountRules(ctx, db, "SELECT "+discountRuleColumns+" FROM discount_rules ORDER BY min_quantity")
+}
+
+// GetApplicableDiscountRules returns the active rules an order of quantity
+// portions of the given product qualifies for at time at.
+func (repo *RepositoryImpl) GetApplicableDiscountRules(ctx context.Context, tx *sql.Tx, productId string, category string, quantity int, at time.Time) ([]*domain.DiscountRule, error) {
+	query := "SELECT " + discountRuleColumns + " FROM discount_rules WHERE active = TRUE AND min_quantity <= ? AND product_id IN ('', ?) AND category IN ('', ?) " +
+		"AND (valid_from IS NULL OR valid_from <= ?) AND (valid_until IS NULL OR valid_until >= ?)"
+	return repo.queryDiscountRules(ctx, tx, query, quantity, productId, category, at, at)
+}
+
+func (repo *RepositoryImpl) queryDiscountRules(ctx context.Context, q queryer, query string, args ...any) ([]*domain.DiscountRule, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("get discount rules", "error", err.Error())
+		return nil, err
+	}
+	defer rows.Close()
+	var rules []*domain.DiscountRule
+	for rows.Next() {
+		var rule domain.DiscountRule
+		if err := scanDiscountRule(rows, &rule); err != nil {
+			logger.GetLogger("repository-log").Log("get discount rules", "error", err.Error())
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, rows.Err()
+}
+
+func (repo *RepositoryImpl) AddDiscountRule(ctx context.Context, tx *sql.Tx, entity *domain.DiscountRule) error {
+	query := "INSERT INTO discount_rules(id, name, min_quantity, type, value, product_id, category, valid_from, valid_until, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err := tx.ExecContext(ctx, query, entity.Id, entity.Name, entity.MinQuantity, entity.Type, entity.Value, entity.ProductId, entity.Category,
+		entity.ValidFrom, entity.ValidUntil, entity.Active)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("add discount rule", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) UpdateDiscountRule(ctx context.Context, tx *sql.Tx, entity *domain.DiscountRule, id string) error {
+	query := "UPDATE discount_rules SET name = ?, min_quantity = ?, type = ?, value = ?, product_id = ?, category = ?, valid_from = ?, valid_until = ?, active = ? WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, entity.Name, entity.MinQuantity, entity.Type, entity.Value, entity.ProductId, entity.Category,
+		entity.ValidFrom, entity.ValidUntil, entity.Active, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("update discount rule", "error", err.Error())
+		return err
+	}
+	return repo.checkUpdated(ctx, tx, result, "discount_rules", id, "discount rule not found")
+}
+
+// checkUpdated turns an UPDATE by id that touched no rows into an error only
+// when the row is missing. MySQL counts changed rows, so saving a form
+// without changes also affects none.
+func (repo *RepositoryImpl) checkUpdated(ctx context.Context, tx *sql.Tx, result sql.Result, table string, id string, notFound string) error {
+	rowAff, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowAff > 0 {
+		return nil
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE id = ?", id).Scan(&count); err != nil {
+		logger.GetLogger("repository-log").Log("check updated", "error", err.Error())
+		return err
+	}
+	if count == 0 {
+		return errors.New(notFound)
+	}
+	return nil
+}
+
+func (repo *RepositoryImpl) DeleteDiscountRule(ctx context.Context, tx *sql.Tx, id string) error {
+	query := "DELETE FROM discount_rules WHERE id = ?"
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		logger.GetLogger("repository-log").Log("delete discount rule", "error", err.Error())
+		return err
+	}
+	rowAff, err := result.RowsAffected()
+	if err != nil || rowAff == 0 {
+		return errors.New("discount rule not found or already deleted")
+	}
+	return nil
+}
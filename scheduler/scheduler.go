@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	"khaira-admin/logger"
+	"sync"
+	"time"
+)
+
+type Job func(ctx context.Context) error
+
+type task struct {
+	name     string
+	interval time.Duration
+	run      Job
+}
+
+// Scheduler runs background jobs on a fixed interval. Each job runs once at
+// start-up and then on every tick; a slow run delays the next one instead of
+// overlapping it.
+type Scheduler struct {
+	tasks  []task
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+func (s *Scheduler) Every(name string, interval time.Duration, run Job) {
+	s.tasks = append(s.tasks, task{name: name, interval: interval, run: run})
+}
+
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(ctx, t)
+	}
+}
+
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, t task) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		if err := t.run(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger("scheduler-log").Log(t.name, "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"slices"
+)
+
+const maxBulkOrders = 500
+
+// BulkUpdateOrders applies one action to many orders. Each order runs in its
+// own transaction, so one failure does not undo the others; the report lists
+// the outcome per order. Without ids the orders are selected by the filter.
+func (svc *ServiceImpl) BulkUpdateOrders(ctx context.Context, request *web.BulkOrderRequest, filter *domain.OrderFilter, username string) (*domain.BulkOrderReport, error) {
+	if request.Action == domain.BulkActionStatus && !slices.Contains(domain.OrderStatuses, request.Status) {
+		return nil, fmt.Errorf("status %s tidak dikenal", request.Status)
+	}
+	ids := request.Ids
+	if len(ids) == 0 {
+		if !filter.Narrows() {
+			return nil, validationError("pilih pesanan atau isi filter pencarian")
+		}
+		filter.SortBy = domain.OrderSortCreatedAt
+		err := svc.repo.StreamOrders(ctx, svc.db, filter, func(order *domain.Orders) error {
+			if len(ids) == maxBulkOrders {
+				return fmt.Errorf("filter mencakup lebih dari %d pesanan", maxBulkOrders)
+			}
+			ids = append(ids, order.Id)
+			return nil
+		})
+		if err != nil {
+			logger.GetLogger("service-log").Log("bulk update orders", "error", err.Error())
+			return nil, err
+		}
+	}
+
+	report := &domain.BulkOrderReport{Results: []*domain.BulkOrderResult{}}
+	for _, id := range ids {
+		result := &domain.BulkOrderResult{Id: id, Success: true}
+		if err := svc.bulkUpdateOrder(ctx, request, id, username); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+		report.Processed++
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+func (svc *ServiceImpl) bulkUpdateOrder(ctx context.Context, request *web.BulkOrderRequest, id string, username string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		return errors.New("pesanan sudah dibatalkan")
+	}
+
+	switch {
+	case request.Action == domain.BulkActionCancel,
+		request.Action == domain.BulkActionStatus && request.Status == domain.OrderStatusCancelled:
+		return svc.cancelOrder(ctx, tx, order, username)
+	case request.Action == domain.BulkActionStatus:
+		return svc.changeOrderStatus(ctx, tx, order, request.Status, username)
+	case request.Action == domain.BulkActionCourier:
+		if order.Courier == request.Courier {
+			return nil
+		}
+		err = svc.repo.SetOrderCourier(ctx, tx, order.Id, request.Courier)
+		if err != nil {
+			return err
+		}
+		return svc.repo.AddOrderHistory(ctx, tx, &domain.OrderHistory{
+			OrderId:   order.Id,
+			Action:    domain.OrderHistoryEdit,
+			Changes:   []domain.OrderChange{{Field: "courier", From: order.Courier, To: request.Courier}},
+			ChangedBy: username,
+		})
+	}
+	return fmt.Errorf("aksi %s tidak dikenal", request.Action)
+}
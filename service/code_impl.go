@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/repository"
+	"math/big"
+)
+
+const orderCodeAttempts = 10
+
+var errOrderCode = errors.New("gagal membuat kode pesanan, silakan coba lagi")
+
+// newOrderCode draws random codes until one is not taken yet.
+func (svc *ServiceImpl) newOrderCode(ctx context.Context, tx *sql.Tx) (string, error) {
+	alphabet := big.NewInt(int64(len(domain.OrderCodeAlphabet)))
+	for range orderCodeAttempts {
+		code := make([]byte, domain.OrderCodeLength)
+		for i := range code {
+			n, err := rand.Int(rand.Reader, alphabet)
+			if err != nil {
+				return "", err
+			}
+			code[i] = domain.OrderCodeAlphabet[n.Int64()]
+		}
+		taken, err := svc.repo.OrderCodeExists(ctx, tx, domain.OrderCodePrefix+string(code))
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return domain.OrderCodePrefix + string(code), nil
+		}
+	}
+	return "", errOrderCode
+}
+
+// withOrderCode writes a new order code through save. A concurrent order can
+// claim the same code between the check and the write; the unique index on
+// orders.code then rejects it and save is retried with another code.
+func (svc *ServiceImpl) withOrderCode(ctx context.Context, tx *sql.Tx, save func(code string) error) error {
+	for range orderCodeAttempts {
+		code, err := svc.newOrderCode(ctx, tx)
+		if err != nil {
+			return err
+		}
+		err = save(code)
+		if !errors.Is(err, repository.ErrOrderCodeTaken) {
+			return err
+		}
+	}
+	return errOrderCode
+}
+
+// AssignOrderCodes gives a code to orders placed before order codes existed.
+// An order that fails is logged and skipped so it does not hold up the rest;
+// the job stops once a batch assigns nothing.
+func (svc *ServiceImpl) AssignOrderCodes(ctx context.Context) error {
+	failed := make(map[string]bool)
+	for {
+		ids, err := svc.repo.GetOrdersWithoutCode(ctx, svc.db, 500)
+		if err != nil {
+			logger.GetLogger("service-log").Log("assign order codes", "error", err.Error())
+			return err
+		}
+		assigned := 0
+		for _, id := range ids {
+			if err := svc.assignOrderCode(ctx, id); err != nil {
+				logger.GetLogger("service-log").Log("assign order codes", "error", fmt.Sprintf("order %s: %s", id, err.Error()))
+				failed[id] = true
+				continue
+			}
+			delete(failed, id)
+			assigned++
+		}
+		if len(ids) < 500 || assigned == 0 {
+			break
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d pesanan gagal diberi kode", len(failed))
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) assignOrderCode(ctx context.Context, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	return svc.withOrderCode(ctx, tx, func(code string) error {
+		return svc.repo.SetOrderCode(ctx, tx, id, code)
+	})
+}
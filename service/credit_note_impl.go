@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) GetCreditNotes(ctx context.Context, username string) ([]*domain.CreditNote, error) {
+	notes, err := svc.repo.GetCreditNotes(ctx, svc.db, username)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get credit notes", "error", err.Error())
+		return nil, err
+	}
+	return notes, nil
+}
+
+// ApplyCreditNote pays an order from a credit note of the same customer. The
+// payment is recorded like any other so voiding it gives the balance back.
+func (svc *ServiceImpl) ApplyCreditNote(ctx context.Context, orderId string, request *web.ApplyCreditNoteRequest, receivedBy string) (data *domain.Payment, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("apply credit note", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("apply credit note", "error", err.Error())
+		return nil, err
+	}
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		err = errors.New("pesanan sudah dibatalkan")
+		return nil, err
+	}
+	note, err := svc.repo.LockCreditNote(ctx, tx, request.CreditNoteId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("apply credit note", "error", err.Error())
+		return nil, err
+	}
+	if note.Username != order.Username {
+		err = errors.New("nota kredit milik pelanggan lain")
+		return nil, err
+	}
+	outstanding := order.Total - order.AmountPaid
+	amount := request.Amount
+	if amount == 0 {
+		amount = min(note.Balance, outstanding)
+	}
+	switch {
+	case amount <= 0:
+		err = errors.New("pesanan sudah lunas")
+		return nil, err
+	case amount > note.Balance:
+		err = errors.New("saldo nota kredit tidak cukup")
+		return nil, err
+	case amount > outstanding:
+		err = errors.New("jumlah melebihi sisa tagihan")
+		return nil, err
+	}
+	err = svc.repo.AdjustCreditNoteBalance(ctx, tx, note.Id, -amount)
+	if err != nil {
+		logger.GetLogger("service-log").Log("apply credit note", "error", err.Error())
+		return nil, err
+	}
+	now := time.Now()
+	payment := &domain.Payment{
+		Id:         uuid.New().String(),
+		OrderId:    order.Id,
+		Method:     domain.PaymentMethodCreditNote,
+		Amount:     amount,
+		Reference:  note.Id,
+		ReceivedBy: receivedBy,
+		PaidAt:     &now,
+	}
+	err = svc.repo.AddPayment(ctx, tx, payment)
+	if err != nil {
+		logger.GetLogger("service-log").Log("apply credit note", "error", err.Error())
+		return nil, err
+	}
+	err = svc.repo.ClearReservation(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("apply credit note", "error", err.Error())
+		return nil, err
+	}
+	return payment, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) GetDeliveryZones(ctx context.Context) ([]*domain.DeliveryZone, error) {
+	result, err := svc.repo.GetDeliveryZones(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get delivery zones", "error", err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+func (svc *ServiceImpl) AddDeliveryZone(ctx context.Context, entity *domain.DeliveryZone) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add delivery zone", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	entity.Id = uuid.New().String()
+	err = svc.normaliseZone(entity)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add delivery zone", "error", err.Error())
+		return err
+	}
+	err = svc.repo.AddDeliveryZone(ctx, tx, entity)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add delivery zone", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) UpdateDeliveryZone(ctx context.Context, entity *domain.DeliveryZone, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update delivery zone", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	entity.Id = id
+	err = svc.normaliseZone(entity)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update delivery zone", "error", err.Error())
+		return err
+	}
+	err = svc.repo.UpdateDeliveryZone(ctx, tx, entity, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update delivery zone", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) DeleteDeliveryZone(ctx context.Context, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete delivery zone", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.DeleteDeliveryZone(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete delivery zone", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// applyDeliveryZone checks that the order address is served and has reached
+// the zone's minimum order, then sets the delivery fee on the order. Until an
+// admin has set up any zone every address is served without a fee, so a fresh
+// install keeps taking orders.
+func (svc *ServiceImpl) applyDeliveryZone(ctx context.Context, tx *sql.Tx, order *domain.Orders) error {
+	zone, err := svc.repo.FindDeliveryZone(ctx, tx, strings.TrimSpace(order.Kecamatan), strings.TrimSpace(order.Desa))
+	if errors.Is(err, sql.ErrNoRows) {
+		zones, err := svc.repo.CountDeliveryZones(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if zones == 0 {
+			logger.GetLogger("service-log").Log("apply delivery zone", "warn", "no delivery zones set up, delivering without a fee")
+			order.DeliveryFee = 0
+			return nil
+		}
+		return validationError("alamat di kecamatan %s belum termasuk area pengiriman", order.Kecamatan)
+	}
+	if err != nil {
+		return err
+	}
+	if !zone.Deliverable {
+		if zone.Desa != "" {
+			return validationError("pengiriman ke desa %s, kecamatan %s tidak tersedia", zone.Desa, zone.Kecamatan)
+		}
+		return validationError("pengiriman ke kecamatan %s tidak tersedia", zone.Kecamatan)
+	}
+	if order.Subtotal < float64(zone.MinOrder) {
+		return validationError("minimal pemesanan untuk pengiriman ke kecamatan %s adalah %s", zone.Kecamatan, helper.FormatRupiah(float64(zone.MinOrder)))
+	}
+	order.DeliveryFee = float64(zone.Fee)
+	return nil
+}
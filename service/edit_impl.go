@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var editableOrderStatuses = []string{domain.OrderStatusPending, domain.OrderStatusVerifying, domain.OrderStatusConfirmed}
+
+// EditOrder changes the items, address or delivery schedule of an order that
+// has not gone to the kitchen yet. Totals are recomputed, stock is moved by
+// the difference and the changed fields are written to the order history, all
+// in one transaction.
+func (svc *ServiceImpl) EditOrder(ctx context.Context, request *web.EditOrderRequest, id string, username string) (data *domain.Orders, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("edit order", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("edit order", "error", err.Error())
+		return nil, err
+	}
+	if !slices.Contains(editableOrderStatuses, order.Status) {
+		err = fmt.Errorf("pesanan berstatus %s tidak dapat diubah", order.Status)
+		return nil, err
+	}
+
+	edited := *order
+	err = svc.applyOrderEdit(ctx, request, &edited)
+	if err != nil {
+		return nil, err
+	}
+	if len(orderChanges(order, &edited)) == 0 {
+		return order, nil
+	}
+	product, err := svc.repriceOrder(ctx, tx, order, &edited)
+	if err != nil {
+		return nil, err
+	}
+
+	if edited.ProductId != order.ProductId || edited.Quantity != order.Quantity {
+		err = svc.repo.RestoreStock(ctx, tx, order.ProductId, order.Quantity)
+		if err != nil {
+			logger.GetLogger("service-log").Log("edit order", "error", err.Error())
+			return nil, err
+		}
+		err = svc.repo.DeductStock(ctx, tx, edited.ProductId, edited.Quantity)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = svc.repo.UpdateOrderDetails(ctx, tx, &edited)
+	if err != nil {
+		logger.GetLogger("service-log").Log("edit order", "error", err.Error())
+		return nil, err
+	}
+	// The edited row is already counted in the booked portions, so a zero
+	// quantity checks whether the day still fits.
+	moved := edited.Quantity > order.Quantity || edited.ProductId != order.ProductId || dateString(edited.DeliveryDate) != dateString(order.DeliveryDate)
+	if moved && edited.DeliveryDate != nil {
+		err = svc.checkProductionCapacity(ctx, tx, *edited.DeliveryDate, product.Category, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = svc.repo.AddOrderHistory(ctx, tx, &domain.OrderHistory{
+		OrderId:   order.Id,
+		Action:    domain.OrderHistoryEdit,
+		Changes:   orderChanges(order, &edited),
+		ChangedBy: username,
+	})
+	if err != nil {
+		logger.GetLogger("service-log").Log("edit order", "error", err.Error())
+		return nil, err
+	}
+	if edited.VoucherCode != order.VoucherCode {
+		err = svc.repo.AddOrderHistory(ctx, tx, &domain.OrderHistory{
+			OrderId:   order.Id,
+			Action:    domain.OrderHistoryVoucherRemoved,
+			Changes:   []domain.OrderChange{{Field: "voucher_code", From: order.VoucherCode, To: edited.VoucherCode}},
+			ChangedBy: username,
+		})
+		if err != nil {
+			logger.GetLogger("service-log").Log("edit order", "error", err.Error())
+			return nil, err
+		}
+	}
+	return &edited, nil
+}
+
+func (svc *ServiceImpl) applyOrderEdit(ctx context.Context, request *web.EditOrderRequest, order *domain.Orders) error {
+	if request.ProductId != nil {
+		order.ProductId = *request.ProductId
+	}
+	if request.Variant != nil {
+		order.Variant = *request.Variant
+	}
+	if request.Quantity != nil {
+		order.Quantity = *request.Quantity
+	}
+	if request.Alamat != nil {
+		order.Alamat = *request.Alamat
+	}
+	if request.Kecamatan != nil || request.Desa != nil {
+		if request.Kecamatan != nil {
+			order.Kecamatan = *request.Kecamatan
+		}
+		if request.Desa != nil {
+			order.Desa = *request.Desa
+		}
+		order.KecamatanCode, order.DesaCode = "", ""
+		if err := svc.normaliseAddress(order); err != nil {
+			return err
+		}
+	}
+	if request.DeliverySlot != nil {
+		order.DeliverySlot = *request.DeliverySlot
+	}
+	if request.DeliveryDate != nil {
+		date, err := domain.ParseDate(*request.DeliveryDate)
+		if err != nil {
+			return err
+		}
+		if order.DeliveryDate == nil || !date.Equal(order.DeliveryDate.Time) {
+			if err := svc.checkOrderSchedule(ctx, date); err != nil {
+				return err
+			}
+			order.DeliveryDate = &date
+		}
+	}
+	return nil
+}
+
+// repriceOrder recomputes subtotal, delivery fee, discount and total of an
+// edited order. The unit price agreed when the order was placed is kept unless
+// the product itself changes, so later price changes and quoted prices are
+// not applied retroactively.
+func (svc *ServiceImpl) repriceOrder(ctx context.Context, tx *sql.Tx, order *domain.Orders, edited *domain.Orders) (*domain.Domain, error) {
+	product, err := svc.repo.GetProductById(ctx, tx, edited.ProductId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("produk %s tidak ditemukan", edited.ProductId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	quoted, err := svc.repo.IsQuotedOrder(ctx, tx, order.Id)
+	if err != nil {
+		return nil, err
+	}
+	if quoted && edited.ProductId != order.ProductId {
+		return nil, errors.New("produk pada pesanan dari penawaran tidak dapat diganti")
+	}
+
+	unitPrice := float64(product.Price)
+	if edited.ProductId == order.ProductId && order.Quantity > 0 {
+		unitPrice = order.Subtotal / float64(order.Quantity)
+	}
+	edited.ProductName = product.Name
+	edited.Subtotal = unitPrice * float64(edited.Quantity)
+	err = svc.applyDeliveryZone(ctx, tx, edited)
+	if err != nil {
+		return nil, err
+	}
+	if quoted {
+		if edited.KecamatanCode == order.KecamatanCode && edited.DesaCode == order.DesaCode {
+			edited.DeliveryFee = order.DeliveryFee
+		}
+	} else {
+		edited.Discount, err = svc.ruleDiscount(ctx, tx, edited, product)
+		if err != nil {
+			return nil, err
+		}
+		if edited.VoucherCode != "" {
+			err = svc.repriceVoucher(ctx, tx, edited, product)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	edited.Total = edited.Subtotal - edited.Discount + edited.DeliveryFee
+	return product, nil
+}
+
+// repriceVoucher recomputes the voucher part of the discount. The redemption
+// already counts towards the usage limits, so only the order conditions are
+// checked again. An edit that takes the subtotal below the voucher minimum
+// drops the voucher and gives its usage back; EditOrder records the removal
+// in the order history.
+func (svc *ServiceImpl) repriceVoucher(ctx context.Context, tx *sql.Tx, order *domain.Orders, product *domain.Domain) error {
+	voucher, err := svc.repo.LockVoucherByCode(ctx, tx, order.VoucherCode)
+	if err != nil {
+		return err
+	}
+	if order.Subtotal < voucher.MinOrder {
+		order.VoucherCode = ""
+		return svc.repo.ReleaseVoucherRedemption(ctx, tx, order.Id)
+	}
+	if err := checkVoucherFits(voucher, order, product); err != nil {
+		return err
+	}
+	amount := domain.DiscountAmount(voucher.Type, voucher.Value, voucher.MaxDiscount, order.Subtotal-order.Discount)
+	order.Discount += amount
+	return svc.repo.UpdateVoucherRedemption(ctx, tx, order.Id, amount)
+}
+
+func orderChanges(before *domain.Orders, after *domain.Orders) []domain.OrderChange {
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"product_id", before.ProductId, after.ProductId},
+		{"variant", before.Variant, after.Variant},
+		{"quantity", strconv.Itoa(before.Quantity), strconv.Itoa(after.Quantity)},
+		{"alamat", before.Alamat, after.Alamat},
+		{"kecamatan", before.Kecamatan, after.Kecamatan},
+		{"desa", before.Desa, after.Desa},
+		{"delivery_date", dateString(before.DeliveryDate), dateString(after.DeliveryDate)},
+		{"delivery_slot", before.DeliverySlot, after.DeliverySlot},
+		{"subtotal", amountString(before.Subtotal), amountString(after.Subtotal)},
+		{"discount", amountString(before.Discount), amountString(after.Discount)},
+		{"delivery_fee", amountString(before.DeliveryFee), amountString(after.DeliveryFee)},
+		{"total", amountString(before.Total), amountString(after.Total)},
+	}
+	changes := []domain.OrderChange{}
+	for _, field := range fields {
+		if strings.TrimSpace(field.from) != strings.TrimSpace(field.to) {
+			changes = append(changes, domain.OrderChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	return changes
+}
+
+func dateString(date *domain.Date) string {
+	if date == nil {
+		return ""
+	}
+	return date.String()
+}
+
+func amountString(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+)
+
+// expandOrders loads the requested relations onto the orders. Notes, payments
+// and history are fetched for all orders in one query each. Products and
+// customers shared by several orders are fetched once; a product or customer
+// that no longer exists is left empty.
+func (svc *ServiceImpl) expandOrders(ctx context.Context, orders []*domain.Orders, expand domain.OrderExpand) error {
+	if expand.Notes {
+		if err := svc.expandOrderNotes(ctx, orders); err != nil {
+			return err
+		}
+	}
+	if expand.Payments {
+		if err := svc.expandOrderPayments(ctx, orders); err != nil {
+			return err
+		}
+	}
+	if expand.History {
+		if err := svc.expandOrderHistory(ctx, orders); err != nil {
+			return err
+		}
+	}
+	products := map[string]*domain.Domain{}
+	customers := map[string]*domain.Users{}
+	for _, order := range orders {
+		if expand.Product {
+			product, ok := products[order.ProductId]
+			if !ok {
+				var err error
+				product, err = svc.repo.GetProductDetail(ctx, svc.db, order.ProductId)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return err
+				}
+				products[order.ProductId] = product
+			}
+			order.Product = product
+		}
+		if expand.Customer {
+			customer, ok := customers[order.Username]
+			if !ok {
+				var err error
+				customer, err = svc.repo.GetUserByUsername(ctx, svc.db, order.Username)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return err
+				}
+				customers[order.Username] = customer
+			}
+			order.Customer = customer
+		}
+	}
+	return nil
+}
+
+func orderIdsOf(orders []*domain.Orders) []string {
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.Id
+	}
+	return ids
+}
+
+func (svc *ServiceImpl) expandOrderNotes(ctx context.Context, orders []*domain.Orders) error {
+	notes, err := svc.repo.GetOrderNotes(ctx, svc.db, orderIdsOf(orders))
+	if err != nil {
+		return err
+	}
+	byOrder := map[string][]*domain.OrderNote{}
+	for _, note := range notes {
+		byOrder[note.OrderId] = append(byOrder[note.OrderId], note)
+	}
+	for _, order := range orders {
+		order.Notes = domain.ThreadNotes(byOrder[order.Id])
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) expandOrderPayments(ctx context.Context, orders []*domain.Orders) error {
+	payments, err := svc.repo.GetPaymentsByOrders(ctx, svc.db, orderIdsOf(orders))
+	if err != nil {
+		return err
+	}
+	byOrder := map[string][]*domain.Payment{}
+	for _, payment := range payments {
+		byOrder[payment.OrderId] = append(byOrder[payment.OrderId], payment)
+	}
+	for _, order := range orders {
+		order.Payments = byOrder[order.Id]
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) expandOrderHistory(ctx context.Context, orders []*domain.Orders) error {
+	history, err := svc.repo.GetOrderHistories(ctx, svc.db, orderIdsOf(orders))
+	if err != nil {
+		return err
+	}
+	byOrder := map[string][]*domain.OrderHistory{}
+	for _, entry := range history {
+		byOrder[entry.OrderId] = append(byOrder[entry.OrderId], entry)
+	}
+	for _, order := range orders {
+		order.History = byOrder[order.Id]
+	}
+	return nil
+}
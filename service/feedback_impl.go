@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrFeedbackSubmitted is returned when feedback for an order is sent again.
+var ErrFeedbackSubmitted = errors.New("ulasan untuk pesanan ini sudah dikirim")
+
+// SubmitFeedback takes the customer's feedback through the order's tracking
+// token, so only whoever received the tracking link can rate the order.
+func (svc *ServiceImpl) SubmitFeedback(ctx context.Context, token string, request *web.FeedbackRequest) (data *domain.Feedback, err error) {
+	order, err := svc.repo.GetOrderByTrackingToken(ctx, svc.db, token)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("submit feedback", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err = svc.repo.LockOrderById(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("submit feedback", "error", err.Error())
+		return nil, err
+	}
+	if order.Status != domain.OrderStatusDelivered {
+		err = errors.New("ulasan bisa dikirim setelah pesanan diterima")
+		return nil, err
+	}
+	submitted, err := svc.repo.HasFeedback(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("submit feedback", "error", err.Error())
+		return nil, err
+	}
+	if submitted {
+		err = ErrFeedbackSubmitted
+		return nil, err
+	}
+	feedback := &domain.Feedback{
+		Id:            uuid.New().String(),
+		OrderId:       order.Id,
+		ProductId:     order.ProductId,
+		Username:      order.Username,
+		Rating:        request.Rating,
+		ProductRating: request.ProductRating,
+		Comment:       strings.TrimSpace(request.Comment),
+		Status:        domain.FeedbackStatusPending,
+	}
+	err = svc.repo.AddFeedback(ctx, tx, feedback)
+	if err != nil {
+		logger.GetLogger("service-log").Log("submit feedback", "error", err.Error())
+		return nil, err
+	}
+	return feedback, nil
+}
+
+func (svc *ServiceImpl) GetFeedback(ctx context.Context, status string, productId string) ([]*domain.Feedback, error) {
+	if status == "" {
+		status = domain.FeedbackStatusPending
+	}
+	feedback, err := svc.repo.GetFeedback(ctx, svc.db, status, productId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get feedback", "error", err.Error())
+		return nil, err
+	}
+	return feedback, nil
+}
+
+func (svc *ServiceImpl) ModerateFeedback(ctx context.Context, id string, status string, moderator string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("moderate feedback", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.ModerateFeedback(ctx, tx, id, status, moderator)
+	if err != nil {
+		logger.GetLogger("service-log").Log("moderate feedback", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// rateProducts pairs each product with the average of its approved ratings.
+// Products nobody has rated yet get a count of 0.
+func (svc *ServiceImpl) rateProducts(ctx context.Context, products []*domain.Domain) ([]*domain.RatedProduct, error) {
+	ratings, err := svc.repo.GetProductRatings(ctx, svc.db)
+	if err != nil {
+		return nil, err
+	}
+	byProduct := make(map[string]*domain.ProductRating, len(ratings))
+	for _, rating := range ratings {
+		byProduct[rating.ProductId] = rating
+	}
+	rated := make([]*domain.RatedProduct, len(products))
+	for i, product := range products {
+		rated[i] = &domain.RatedProduct{Domain: product}
+		if rating, ok := byProduct[product.Id]; ok {
+			rated[i].AverageRating = rating.AverageRating
+			rated[i].RatingCount = rating.RatingCount
+		}
+	}
+	return rated, nil
+}
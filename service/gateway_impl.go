@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/gateway"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) CreatePaymentLink(ctx context.Context, orderId string) (data *domain.PaymentLink, err error) {
+	order, err := svc.repo.GetOrderById(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("create payment link", "error", err.Error())
+		return nil, err
+	}
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		return nil, errors.New("pesanan sudah dibatalkan")
+	}
+	payments, err := svc.repo.GetPaymentsByOrder(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("create payment link", "error", err.Error())
+		return nil, err
+	}
+	refunds, err := svc.repo.GetRefundsByOrder(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("create payment link", "error", err.Error())
+		return nil, err
+	}
+	summary := summarisePayments(order, payments, refunds)
+	if summary.Outstanding <= 0 {
+		return nil, errors.New("pesanan sudah lunas")
+	}
+
+	// The provider refuses a reused order_id, so every link gets its own.
+	data = &domain.PaymentLink{
+		Id:              uuid.New().String(),
+		OrderId:         order.Id,
+		Provider:        svc.gateway.Name(),
+		ProviderOrderId: fmt.Sprintf("%s-%d", order.Id, time.Now().Unix()),
+		Amount:          summary.Outstanding,
+		Status:          gateway.StatusPending,
+	}
+	link, err := svc.gateway.CreatePaymentLink(ctx, gateway.LinkRequest{OrderId: data.ProviderOrderId, Amount: data.Amount})
+	if err != nil {
+		logger.GetLogger("service-log").Log("create payment link", "error", err.Error())
+		return nil, err
+	}
+	data.Token = link.Token
+	data.RedirectURL = link.RedirectURL
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("create payment link", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.AddPaymentLink(ctx, tx, data)
+	if err != nil {
+		logger.GetLogger("service-log").Log("create payment link", "error", err.Error())
+		return nil, err
+	}
+	return data, nil
+}
+
+// HandlePaymentNotification applies a provider callback. Providers resend
+// notifications, so a link that already produced a payment or waits for
+// review is left alone. A paid notification for another amount than the link
+// was created for is not trusted and the link is put up for review. Money
+// that arrives after the order was cancelled is recorded so it can be
+// refunded, but the order stays cancelled.
+func (svc *ServiceImpl) HandlePaymentNotification(ctx context.Context, body []byte) (err error) {
+	notification, err := svc.gateway.ParseNotification(body)
+	if err != nil {
+		logger.GetLogger("service-log").Log("payment notification", "error", err.Error())
+		return err
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("payment notification", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	link, err := svc.repo.LockPaymentLink(ctx, tx, svc.gateway.Name(), notification.OrderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("payment notification", "error", err.Error())
+		return err
+	}
+	if link.PaymentId != "" || link.Status == notification.Status || link.Status == gateway.StatusReview {
+		return nil
+	}
+	link.Status = notification.Status
+	link.TransactionId = notification.TransactionId
+
+	if notification.Status == gateway.StatusPaid {
+		link.PaidAmount = notification.Amount
+	}
+	if notification.Status == gateway.StatusPaid && notification.Amount != link.Amount {
+		logger.GetLogger("service-log").Log("payment notification", "warn",
+			fmt.Sprintf("link %s paid %s instead of %s", link.Id, helper.FormatRupiah(notification.Amount), helper.FormatRupiah(link.Amount)))
+		link.Status = gateway.StatusReview
+	}
+	if link.Status == gateway.StatusPaid {
+		cancelled, err := svc.bookLinkPayment(ctx, tx, link, "")
+		if err != nil {
+			logger.GetLogger("service-log").Log("payment notification", "error", err.Error())
+			return err
+		}
+		if cancelled {
+			logger.GetLogger("service-log").Log("payment notification", "warn",
+				fmt.Sprintf("link %s paid after order %s was cancelled", link.Id, link.OrderId))
+			link.Status = gateway.StatusReview
+		}
+	}
+	err = svc.repo.UpdatePaymentLink(ctx, tx, link)
+	if err != nil {
+		logger.GetLogger("service-log").Log("payment notification", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// bookLinkPayment records the amount a link collected as a payment. The order
+// is confirmed unless it was cancelled in the meantime; then the payment is
+// only kept so it can be refunded and cancelled is true.
+func (svc *ServiceImpl) bookLinkPayment(ctx context.Context, tx *sql.Tx, link *domain.PaymentLink, changedBy string) (cancelled bool, err error) {
+	order, err := svc.repo.LockOrderById(ctx, tx, link.OrderId)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	payment := &domain.Payment{
+		Id:         uuid.New().String(),
+		OrderId:    order.Id,
+		Method:     domain.PaymentMethodGateway,
+		Amount:     link.PaidAmount,
+		Reference:  link.TransactionId,
+		ReceivedBy: link.Provider,
+		PaidAt:     &now,
+	}
+	if err = svc.repo.AddPayment(ctx, tx, payment); err != nil {
+		return false, err
+	}
+	link.PaymentId = payment.Id
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		return true, nil
+	}
+	if err = svc.repo.ClearReservation(ctx, tx, order.Id); err != nil {
+		return false, err
+	}
+	if order.Status == domain.OrderStatusPending || order.Status == domain.OrderStatusVerifying {
+		if err = svc.changeOrderStatus(ctx, tx, order, domain.OrderStatusConfirmed, changedBy); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// GetPaymentLinks lists payment links with the given status, by default the
+// ones waiting for review.
+func (svc *ServiceImpl) GetPaymentLinks(ctx context.Context, status string) ([]*domain.PaymentLink, error) {
+	if status == "" {
+		status = gateway.StatusReview
+	}
+	links, err := svc.repo.GetPaymentLinks(ctx, svc.db, status)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get payment links", "error", err.Error())
+		return nil, err
+	}
+	return links, nil
+}
+
+// ResolvePaymentLink settles a link held for review. Recording books the
+// amount the provider collected, even when it differs from the link amount;
+// the payment summary then shows any shortfall or overpayment. Dismissing
+// closes a link whose money was sent back outside the app, which is only
+// possible while nothing was booked for it.
+func (svc *ServiceImpl) ResolvePaymentLink(ctx context.Context, id string, action string, resolver string) (data *domain.PaymentLink, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("resolve payment link", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	link, err := svc.repo.LockPaymentLinkById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("resolve payment link", "error", err.Error())
+		return nil, err
+	}
+	if link.Status != gateway.StatusReview {
+		err = validationError("tautan pembayaran tidak sedang ditinjau")
+		return nil, err
+	}
+	switch action {
+	case web.ResolveLinkRecord:
+		if link.PaymentId == "" {
+			if _, err = svc.bookLinkPayment(ctx, tx, link, resolver); err != nil {
+				logger.GetLogger("service-log").Log("resolve payment link", "error", err.Error())
+				return nil, err
+			}
+		}
+		link.Status = gateway.StatusPaid
+	case web.ResolveLinkDismiss:
+		if link.PaymentId != "" {
+			err = validationError("pembayaran sudah dicatat, ajukan refund untuk mengembalikannya")
+			return nil, err
+		}
+		link.Status = gateway.StatusFailed
+	default:
+		err = validationError("tindakan %s tidak dikenal", action)
+		return nil, err
+	}
+	err = svc.repo.UpdatePaymentLink(ctx, tx, link)
+	if err != nil {
+		logger.GetLogger("service-log").Log("resolve payment link", "error", err.Error())
+		return nil, err
+	}
+	return link, nil
+}
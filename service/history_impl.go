@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (svc *ServiceImpl) GetOrderHistory(ctx context.Context, orderId string) ([]*domain.OrderHistory, error) {
+	history, err := svc.repo.GetOrderHistory(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order history", "error", err.Error())
+		return nil, err
+	}
+	return history, nil
+}
+
+// changeOrderStatus moves an order to a new status and records the transition
+// in its history. changedBy is empty when the system made the change.
+func (svc *ServiceImpl) changeOrderStatus(ctx context.Context, tx *sql.Tx, order *domain.Orders, status string, changedBy string) error {
+	if order.Status == status {
+		return nil
+	}
+	err := svc.repo.UpdateOrder(ctx, tx, &domain.Orders{Status: status}, order.Id)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.AddOrderHistory(ctx, tx, &domain.OrderHistory{
+		OrderId:   order.Id,
+		Action:    domain.OrderHistoryStatus,
+		Changes:   []domain.OrderChange{{Field: "status", From: order.Status, To: status}},
+		ChangedBy: changedBy,
+	})
+	if err != nil {
+		return err
+	}
+	order.Status = status
+	return nil
+}
+
+// cancelOrder cancels the order and gives back what it held: the stock and the
+// voucher usage.
+func (svc *ServiceImpl) cancelOrder(ctx context.Context, tx *sql.Tx, order *domain.Orders, changedBy string) error {
+	err := svc.changeOrderStatus(ctx, tx, order, domain.OrderStatusCancelled, changedBy)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.RestoreStock(ctx, tx, order.ProductId, order.Quantity)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.ReleaseVoucherRedemption(ctx, tx, order.Id)
+	if err != nil {
+		return err
+	}
+	return svc.repo.ClearReservation(ctx, tx, order.Id)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"time"
+)
+
+const idempotencyKeyTTL = 24 * time.Hour
+
+// ErrIdempotencyKeyReused is returned when a key comes back with a request
+// body that differs from the one it was first used with.
+var ErrIdempotencyKeyReused = errors.New("idempotency key sudah dipakai untuk permintaan yang berbeda")
+
+// AddOrdersIdempotent creates the order at most once per key. A retry with the
+// same key and body returns the stored record with Replayed set instead of
+// placing a second order.
+func (svc *ServiceImpl) AddOrdersIdempotent(ctx context.Context, key *domain.IdempotencyKey, orderDetails *domain.Orders) (*domain.IdempotencyKey, error) {
+	record, err := svc.replayIdempotencyKey(ctx, key)
+	if err == nil {
+		return record, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	err = svc.prepareOrder(ctx, orderDetails)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add order", "error", err.Error())
+		return nil, err
+	}
+	claimed, err := svc.placeIdempotentOrder(ctx, key, orderDetails)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add order", "error", err.Error())
+		return nil, err
+	}
+	if !claimed {
+		return svc.replayIdempotencyKey(ctx, key)
+	}
+	key.OrderId = orderDetails.Id
+	return key, nil
+}
+
+func (svc *ServiceImpl) replayIdempotencyKey(ctx context.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, error) {
+	record, err := svc.repo.GetIdempotencyKey(ctx, svc.db, key.Key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if record.Fingerprint != key.Fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	record.Replayed = true
+	return record, nil
+}
+
+// placeIdempotentOrder claims the key and creates the order in one
+// transaction, so a failed order leaves the key free for the next retry.
+func (svc *ServiceImpl) placeIdempotentOrder(ctx context.Context, key *domain.IdempotencyKey, orderDetails *domain.Orders) (claimed bool, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	key.CreatedAt = time.Now()
+	key.ExpiresAt = key.CreatedAt.Add(idempotencyKeyTTL)
+	claimed, err = svc.repo.ClaimIdempotencyKey(ctx, tx, key)
+	if err != nil || !claimed {
+		return false, err
+	}
+	err = svc.placeOrder(ctx, tx, orderDetails)
+	if err != nil {
+		return false, err
+	}
+	err = svc.repo.CompleteIdempotencyKey(ctx, tx, key.Key, orderDetails.Id)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (svc *ServiceImpl) PurgeIdempotencyKeys(ctx context.Context) error {
+	err := svc.repo.DeleteExpiredIdempotencyKeys(ctx, svc.db, time.Now())
+	if err != nil {
+		logger.GetLogger("service-log").Log("purge idempotency keys", "error", err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"time"
+)
+
+func (svc *ServiceImpl) nextInvoiceNumber(ctx context.Context, tx *sql.Tx, at time.Time) (string, error) {
+	at = at.In(helper.Location())
+	number, err := svc.repo.NextInvoiceNumber(ctx, tx, at.Format("2006-01"))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("INV/%s/%04d", at.Format("2006/01"), number), nil
+}
+
+// GetInvoice gives the order's invoice with a tracking link for the QR code.
+// Every order gets its invoice number and tracking token when it is placed;
+// older orders received theirs from migrations 0024 and 0025.
+func (svc *ServiceImpl) GetInvoice(ctx context.Context, orderId string) (*domain.Invoice, error) {
+	order, err := svc.repo.GetOrderById(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get invoice", "error", err.Error())
+		return nil, err
+	}
+	trackingToken, err := svc.repo.GetTrackingToken(ctx, svc.db, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get invoice", "error", err.Error())
+		return nil, err
+	}
+	payments, err := svc.repo.GetPaymentsByOrder(ctx, svc.db, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get invoice", "error", err.Error())
+		return nil, err
+	}
+	refunds, err := svc.repo.GetRefundsByOrder(ctx, svc.db, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get invoice", "error", err.Error())
+		return nil, err
+	}
+	data := &domain.Invoice{
+		Number:        order.InvoiceNumber,
+		IssuedAt:      helper.Now(),
+		Order:         order,
+		Summary:       summarisePayments(order, payments, refunds),
+		TrackingToken: trackingToken,
+	}
+	if order.CreatedAt != nil {
+		data.IssuedAt = order.CreatedAt.In(helper.Location())
+	}
+	return data, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"khaira-admin/web"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) AddOrderNote(ctx context.Context, orderId string, request *web.OrderNoteRequest, author string) (data *domain.OrderNote, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add order note", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add order note", "error", err.Error())
+		return nil, err
+	}
+	if request.ParentId != "" {
+		parent, err := svc.repo.GetOrderNote(ctx, tx, request.ParentId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			logger.GetLogger("service-log").Log("add order note", "error", err.Error())
+			return nil, err
+		}
+		if parent == nil || parent.OrderId != order.Id {
+			return nil, errors.New("catatan yang dibalas tidak ditemukan pada pesanan ini")
+		}
+	}
+	note := &domain.OrderNote{
+		Id:       uuid.New().String(),
+		OrderId:  order.Id,
+		ParentId: request.ParentId,
+		Author:   author,
+		Body:     strings.TrimSpace(request.Body),
+	}
+	err = svc.repo.AddOrderNote(ctx, tx, note)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add order note", "error", err.Error())
+		return nil, err
+	}
+	now := helper.Now()
+	note.CreatedAt = &now
+	return note, nil
+}
+
+func (svc *ServiceImpl) GetOrderNotes(ctx context.Context, orderId string) ([]*domain.OrderNote, error) {
+	notes, err := svc.repo.GetOrderNotes(ctx, svc.db, []string{orderId})
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order notes", "error", err.Error())
+		return nil, err
+	}
+	return domain.ThreadNotes(notes), nil
+}
+
+// orderNoteLines flattens the notes of the given orders into "author: body"
+// lines for printed reports, keyed by order id.
+func (svc *ServiceImpl) orderNoteLines(ctx context.Context, orderIds []string) (map[string][]string, error) {
+	notes, err := svc.repo.GetOrderNotes(ctx, svc.db, orderIds)
+	if err != nil {
+		return nil, err
+	}
+	lines := map[string][]string{}
+	for _, note := range notes {
+		lines[note.OrderId] = append(lines[note.OrderId], note.Author+": "+note.Body)
+	}
+	return lines, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) AddPayment(ctx context.Context, orderId string, payment *domain.Payment) (data *domain.Payment, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add payment", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add payment", "error", err.Error())
+		return nil, err
+	}
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		err = errors.New("pesanan sudah dibatalkan")
+		return nil, err
+	}
+	payment.Id = uuid.New().String()
+	payment.OrderId = order.Id
+	if payment.PaidAt == nil {
+		now := time.Now()
+		payment.PaidAt = &now
+	}
+	err = svc.repo.AddPayment(ctx, tx, payment)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add payment", "error", err.Error())
+		return nil, err
+	}
+	err = svc.repo.ClearReservation(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add payment", "error", err.Error())
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (svc *ServiceImpl) GetPaymentSummary(ctx context.Context, orderId string) (*domain.PaymentSummary, error) {
+	order, err := svc.repo.GetOrderById(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get payment summary", "error", err.Error())
+		return nil, err
+	}
+	payments, err := svc.repo.GetPaymentsByOrder(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get payment summary", "error", err.Error())
+		return nil, err
+	}
+	refunds, err := svc.repo.GetRefundsByOrder(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get payment summary", "error", err.Error())
+		return nil, err
+	}
+	return summarisePayments(order, payments, refunds), nil
+}
+
+func (svc *ServiceImpl) VoidPayment(ctx context.Context, id string, voidedBy string, reason string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("void payment", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	payment, err := svc.repo.GetPaymentById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("void payment", "error", err.Error())
+		return err
+	}
+	err = svc.voidPayment(ctx, tx, payment, voidedBy, reason)
+	if err != nil {
+		logger.GetLogger("service-log").Log("void payment", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// voidPayment is the only way a payment gets voided. A payment with a refund
+// against it cannot be voided, or the money would be given back twice, and a
+// credit note payment puts its amount back on the note.
+func (svc *ServiceImpl) voidPayment(ctx context.Context, tx *sql.Tx, payment *domain.Payment, voidedBy string, reason string) error {
+	refunded, err := svc.repo.SumPaymentRefunds(ctx, tx, payment.Id)
+	if err != nil {
+		return err
+	}
+	if refunded > 0 {
+		return errors.New("pembayaran yang sudah direfund tidak bisa dibatalkan")
+	}
+	err = svc.repo.VoidPayment(ctx, tx, payment.Id, voidedBy, reason)
+	if err != nil {
+		return err
+	}
+	if payment.Method == domain.PaymentMethodCreditNote {
+		return svc.repo.AdjustCreditNoteBalance(ctx, tx, payment.Reference, payment.Amount)
+	}
+	return nil
+}
+
+// summarisePayments works out the balance of an order. Approved refunds are
+// taken off what was paid, the same way the orders' amount_paid is.
+func summarisePayments(order *domain.Orders, payments []*domain.Payment, refunds []*domain.Refund) *domain.PaymentSummary {
+	summary := &domain.PaymentSummary{
+		OrderId:  order.Id,
+		Total:    order.Total,
+		Payments: payments,
+	}
+	if summary.Payments == nil {
+		summary.Payments = []*domain.Payment{}
+	}
+	for _, payment := range payments {
+		if payment.VoidedAt == nil {
+			summary.Paid += payment.Amount
+		}
+	}
+	for _, refund := range refunds {
+		if refund.Status == domain.RefundStatusApproved {
+			summary.Refunded += refund.Amount
+		}
+	}
+	summary.Paid -= summary.Refunded
+	summary.Outstanding = max(summary.Total-summary.Paid, 0)
+	summary.State = domain.PaymentStateOf(summary.Total, summary.Paid)
+	return summary
+}
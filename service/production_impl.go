@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"time"
+)
+
+func (svc *ServiceImpl) GetDailyCapacities(ctx context.Context) ([]*domain.DailyCapacity, error) {
+	result, err := svc.repo.GetDailyCapacities(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get daily capacities", "error", err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+func (svc *ServiceImpl) SaveDailyCapacity(ctx context.Context, entity *domain.DailyCapacity) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("save daily capacity", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.SaveDailyCapacity(ctx, tx, entity)
+	if err != nil {
+		logger.GetLogger("service-log").Log("save daily capacity", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) DeleteDailyCapacity(ctx context.Context, category string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete daily capacity", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.DeleteDailyCapacity(ctx, tx, category)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete daily capacity", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) GetProductionSettings(ctx context.Context) (*domain.ProductionSettings, error) {
+	result, err := svc.repo.GetProductionSettings(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get production settings", "error", err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+func (svc *ServiceImpl) UpdateProductionSettings(ctx context.Context, entity *domain.ProductionSettings) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update production settings", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.UpdateProductionSettings(ctx, tx, entity)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update production settings", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) GetBlackoutDates(ctx context.Context) ([]*domain.BlackoutDate, error) {
+	result, err := svc.repo.GetBlackoutDates(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get blackout dates", "error", err.Error())
+		return nil, err
+	}
+	return result, nil
+}
+
+func (svc *ServiceImpl) AddBlackoutDate(ctx context.Context, entity *domain.BlackoutDate) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add blackout date", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.AddBlackoutDate(ctx, tx, entity)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add blackout date", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) DeleteBlackoutDate(ctx context.Context, date domain.Date) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete blackout date", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.DeleteBlackoutDate(ctx, tx, date)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete blackout date", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// checkOrderSchedule rejects delivery dates that are past the cut-off or fall
+// on a blackout date.
+func (svc *ServiceImpl) checkOrderSchedule(ctx context.Context, date domain.Date) error {
+	settings, err := svc.repo.GetProductionSettings(ctx, svc.db)
+	if err != nil {
+		return err
+	}
+	deadline, err := orderDeadline(settings, date)
+	if err != nil {
+		return err
+	}
+	if helper.Now().After(deadline) {
+		return validationError("pesanan untuk tanggal %s ditutup sejak %s", date, deadline.Format("2006-01-02 15:04"))
+	}
+
+	blackout, err := svc.repo.GetBlackoutDate(ctx, svc.db, date)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if blackout != nil {
+		if blackout.Reason != "" {
+			return validationError("dapur libur pada tanggal %s (%s)", date, blackout.Reason)
+		}
+		return validationError("dapur libur pada tanggal %s", date)
+	}
+	return nil
+}
+
+func orderDeadline(settings *domain.ProductionSettings, date domain.Date) (time.Time, error) {
+	cutoff, err := time.Parse("15:04", settings.CutoffTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cutoff time %q", settings.CutoffTime)
+	}
+	day := date.AddDate(0, 0, -settings.CutoffDays)
+	return time.Date(day.Year(), day.Month(), day.Day(), cutoff.Hour(), cutoff.Minute(), 0, 0, helper.Location()), nil
+}
+
+// firstBookableDate is the earliest delivery date whose cut-off has not passed
+// at now.
+func firstBookableDate(settings *domain.ProductionSettings, now time.Time) (domain.Date, error) {
+	date, err := domain.ParseDate(now.Format(domain.DateLayout))
+	if err != nil {
+		return domain.Date{}, err
+	}
+	for {
+		deadline, err := orderDeadline(settings, date)
+		if err != nil {
+			return domain.Date{}, err
+		}
+		if !now.After(deadline) {
+			return date, nil
+		}
+		date = domain.Date{Time: date.AddDate(0, 0, 1)}
+	}
+}
+
+// checkProductionCapacity must run inside the order transaction: it locks the
+// capacity rows so the booked portions cannot change until the order is saved.
+func (svc *ServiceImpl) checkProductionCapacity(ctx context.Context, tx *sql.Tx, date domain.Date, category string, quantity int) error {
+	capacities, err := svc.repo.LockDailyCapacities(ctx, tx, []string{domain.CapacityOverall, category})
+	if err != nil {
+		return err
+	}
+
+	if limit, ok := capacities[domain.CapacityOverall]; ok {
+		booked, err := svc.repo.SumBookedPortions(ctx, tx, date, "")
+		if err != nil {
+			return err
+		}
+		if booked+quantity > limit {
+			return validationError("kapasitas produksi tanggal %s tersisa %d porsi", date, max(limit-booked, 0))
+		}
+	}
+
+	if limit, ok := capacities[category]; ok {
+		booked, err := svc.repo.SumBookedPortions(ctx, tx, date, category)
+		if err != nil {
+			return err
+		}
+		if booked+quantity > limit {
+			return validationError("kapasitas produksi kategori %s tanggal %s tersisa %d porsi", category, date, max(limit-booked, 0))
+		}
+	}
+	return nil
+}
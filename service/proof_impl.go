@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// paymentProofDir holds the transfer proofs. Unlike the product images in
+// /app/uploads it is not served as static files; proofs are only read through
+// GetPaymentProofFile behind the admin login.
+const paymentProofDir = "/app/proofs"
+
+func (svc *ServiceImpl) UploadPaymentProof(ctx context.Context, orderId string, proof *domain.PaymentProof, file *multipart.FileHeader) (data *domain.PaymentProof, err error) {
+	if err = os.MkdirAll(paymentProofDir, 0o700); err != nil {
+		logger.GetLogger("service-log").Log("upload payment proof", "error", err.Error())
+		return nil, err
+	}
+	filename, err := helper.SaveProofFile(file, paymentProofDir)
+	switch {
+	case errors.Is(err, helper.ErrFileTooLarge):
+		return nil, validationError("ukuran bukti transfer maksimal %d MB", helper.MaxProofSize>>20)
+	case errors.Is(err, helper.ErrUnsupportedFile):
+		return nil, validationError("bukti transfer harus berupa JPG, PNG atau PDF")
+	case err != nil:
+		logger.GetLogger("service-log").Log("upload payment proof", "error", err.Error())
+		return nil, err
+	}
+	finalPath := filepath.Join(paymentProofDir, filename)
+	defer func() {
+		if err != nil {
+			os.Remove(finalPath)
+		}
+	}()
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("upload payment proof", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("upload payment proof", "error", err.Error())
+		return nil, err
+	}
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		err = errors.New("pesanan sudah dibatalkan")
+		return nil, err
+	}
+	proof.Id = uuid.New().String()
+	proof.OrderId = order.Id
+	proof.Image = filename
+	proof.Status = domain.ProofStatusPending
+	err = svc.repo.AddPaymentProof(ctx, tx, proof)
+	if err != nil {
+		logger.GetLogger("service-log").Log("upload payment proof", "error", err.Error())
+		return nil, err
+	}
+	if order.Status == domain.OrderStatusPending {
+		err = svc.changeOrderStatus(ctx, tx, order, domain.OrderStatusVerifying, "")
+		if err != nil {
+			logger.GetLogger("service-log").Log("upload payment proof", "error", err.Error())
+			return nil, err
+		}
+	}
+	return proof, nil
+}
+
+func (svc *ServiceImpl) GetPaymentProofs(ctx context.Context, status string) ([]*domain.PaymentProof, error) {
+	if status == "" {
+		status = domain.ProofStatusPending
+	}
+	proofs, err := svc.repo.GetPaymentProofs(ctx, svc.db, status)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get payment proofs", "error", err.Error())
+		return nil, err
+	}
+	return proofs, nil
+}
+
+// GetPaymentProofFile returns the path of the file uploaded with a transfer
+// proof.
+func (svc *ServiceImpl) GetPaymentProofFile(ctx context.Context, id string) (string, error) {
+	proof, err := svc.repo.GetPaymentProof(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get payment proof file", "error", err.Error())
+		return "", err
+	}
+	return filepath.Join(paymentProofDir, filepath.Base(proof.Image)), nil
+}
+
+// ApprovePaymentProof books the transfer as a payment and releases the order
+// to the kitchen.
+func (svc *ServiceImpl) ApprovePaymentProof(ctx context.Context, id string, reviewer string) (data *domain.Payment, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	proof, err := svc.repo.GetPaymentProofById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+		return nil, err
+	}
+	if proof.Status != domain.ProofStatusPending {
+		err = errors.New("bukti transfer sudah diverifikasi")
+		return nil, err
+	}
+	order, err := svc.repo.LockOrderById(ctx, tx, proof.OrderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+		return nil, err
+	}
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		err = errors.New("pesanan sudah dibatalkan")
+		return nil, err
+	}
+	payment := &domain.Payment{
+		Id:         uuid.New().String(),
+		OrderId:    order.Id,
+		Method:     domain.PaymentMethodTransfer,
+		Amount:     proof.Amount,
+		Reference:  proof.Id,
+		Proof:      proof.Image,
+		ReceivedBy: reviewer,
+		PaidAt:     proof.CreatedAt,
+	}
+	err = svc.repo.AddPayment(ctx, tx, payment)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+		return nil, err
+	}
+	err = svc.repo.ClearReservation(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+		return nil, err
+	}
+	proof.Status = domain.ProofStatusApproved
+	proof.ReviewedBy = reviewer
+	proof.PaymentId = payment.Id
+	err = svc.repo.ReviewPaymentProof(ctx, tx, proof)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+		return nil, err
+	}
+	if order.Status == domain.OrderStatusPending || order.Status == domain.OrderStatusVerifying {
+		err = svc.changeOrderStatus(ctx, tx, order, domain.OrderStatusConfirmed, reviewer)
+		if err != nil {
+			logger.GetLogger("service-log").Log("approve payment proof", "error", err.Error())
+			return nil, err
+		}
+	}
+	return payment, nil
+}
+
+// RejectPaymentProof also voids the payment of a proof that was approved by
+// mistake. The order goes back to pending once nothing else backs it.
+func (svc *ServiceImpl) RejectPaymentProof(ctx context.Context, id string, reviewer string, reason string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	proof, err := svc.repo.GetPaymentProofById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	if proof.Status == domain.ProofStatusRejected {
+		err = errors.New("bukti transfer sudah ditolak")
+		return err
+	}
+	order, err := svc.repo.LockOrderById(ctx, tx, proof.OrderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	if proof.Status == domain.ProofStatusApproved && proof.PaymentId != "" {
+		var payment *domain.Payment
+		payment, err = svc.repo.GetPaymentById(ctx, tx, proof.PaymentId)
+		if err != nil {
+			logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+			return err
+		}
+		// The payment may already have been voided by hand before the proof
+		// was rejected.
+		if payment.VoidedAt == nil {
+			err = svc.voidPayment(ctx, tx, payment, reviewer, reason)
+			if err != nil {
+				logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+				return err
+			}
+		}
+	}
+	proof.Status = domain.ProofStatusRejected
+	proof.ReviewedBy = reviewer
+	proof.RejectReason = reason
+	err = svc.repo.ReviewPaymentProof(ctx, tx, proof)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	if order.Status != domain.OrderStatusVerifying && order.Status != domain.OrderStatusConfirmed {
+		return nil
+	}
+	pending, err := svc.repo.CountPendingProofs(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	paid, err := svc.repo.SumPayments(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	status := order.Status
+	switch {
+	case paid > 0:
+	case pending > 0:
+		status = domain.OrderStatusVerifying
+	default:
+		status = domain.OrderStatusPending
+	}
+	if status == order.Status {
+		return nil
+	}
+	err = svc.changeOrderStatus(ctx, tx, order, status, reviewer)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject payment proof", "error", err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+
+	"github.com/google/uuid"
+)
+
+// priceQuotation copies product names onto the items; a unit price of 0
+// takes the catalogue price.
+func (svc *ServiceImpl) priceQuotation(ctx context.Context, tx *sql.Tx, quotation *domain.Quotation) error {
+	for _, item := range quotation.Items {
+		product, err := svc.repo.GetProductById(ctx, tx, item.ProductId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return validationError("produk %s tidak ditemukan", item.ProductId)
+			}
+			return err
+		}
+		item.ProductName = product.Name
+		if item.UnitPrice == 0 {
+			item.UnitPrice = float64(product.Price)
+		}
+	}
+	quotation.Price()
+	return nil
+}
+
+func (svc *ServiceImpl) AddQuotation(ctx context.Context, quotation *domain.Quotation) (data *domain.Quotation, err error) {
+	err = svc.normaliseQuotation(quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add quotation", "error", err.Error())
+		return nil, err
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add quotation", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.priceQuotation(ctx, tx, quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add quotation", "error", err.Error())
+		return nil, err
+	}
+	quotation.Id = uuid.New().String()
+	quotation.Revision = 1
+	quotation.Status = domain.QuotationStatusDraft
+	err = svc.repo.AddQuotation(ctx, tx, quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add quotation", "error", err.Error())
+		return nil, err
+	}
+	err = svc.repo.AddQuotationRevision(ctx, tx, quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add quotation", "error", err.Error())
+		return nil, err
+	}
+	return quotation, nil
+}
+
+func (svc *ServiceImpl) GetQuotations(ctx context.Context, status string) ([]*domain.Quotation, error) {
+	quotations, err := svc.repo.GetQuotations(ctx, svc.db, status)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get quotations", "error", err.Error())
+		return nil, err
+	}
+	return quotations, nil
+}
+
+func (svc *ServiceImpl) GetQuotation(ctx context.Context, id string, revision int) (*domain.Quotation, error) {
+	quotation, err := svc.repo.GetQuotation(ctx, svc.db, id, revision)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get quotation", "error", err.Error())
+		return nil, err
+	}
+	return quotation, nil
+}
+
+// ReviseQuotation stores the new terms as the next revision and puts the
+// quotation back into draft so it has to be sent again.
+func (svc *ServiceImpl) ReviseQuotation(ctx context.Context, id string, quotation *domain.Quotation) (data *domain.Quotation, err error) {
+	err = svc.normaliseQuotation(quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("revise quotation", "error", err.Error())
+		return nil, err
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("revise quotation", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	current, err := svc.repo.LockQuotation(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("revise quotation", "error", err.Error())
+		return nil, err
+	}
+	if current.Status == domain.QuotationStatusAccepted || current.Status == domain.QuotationStatusConverted {
+		err = validationError("penawaran yang sudah %s tidak dapat direvisi", current.Status)
+		return nil, err
+	}
+	err = svc.priceQuotation(ctx, tx, quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("revise quotation", "error", err.Error())
+		return nil, err
+	}
+	quotation.Id = current.Id
+	quotation.Revision = current.Revision + 1
+	quotation.Status = domain.QuotationStatusDraft
+	quotation.CreatedAt = current.CreatedAt
+	err = svc.repo.UpdateQuotation(ctx, tx, quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("revise quotation", "error", err.Error())
+		return nil, err
+	}
+	err = svc.repo.AddQuotationRevision(ctx, tx, quotation)
+	if err != nil {
+		logger.GetLogger("service-log").Log("revise quotation", "error", err.Error())
+		return nil, err
+	}
+	return quotation, nil
+}
+
+var quotationTransitions = map[string][]string{
+	domain.QuotationStatusDraft:    {domain.QuotationStatusSent, domain.QuotationStatusRejected},
+	domain.QuotationStatusSent:     {domain.QuotationStatusAccepted, domain.QuotationStatusRejected},
+	domain.QuotationStatusRejected: {},
+	domain.QuotationStatusAccepted: {domain.QuotationStatusRejected},
+}
+
+func (svc *ServiceImpl) UpdateQuotationStatus(ctx context.Context, id string, status string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update quotation status", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	quotation, err := svc.repo.LockQuotation(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update quotation status", "error", err.Error())
+		return err
+	}
+	allowed := false
+	for _, next := range quotationTransitions[quotation.Status] {
+		allowed = allowed || next == status
+	}
+	if !allowed {
+		err = validationError("status penawaran tidak dapat diubah dari %s ke %s", quotation.Status, status)
+		return err
+	}
+	if status == domain.QuotationStatusAccepted && quotationExpired(quotation) {
+		err = validationError("penawaran sudah kedaluwarsa sejak %s", quotation.ValidUntil)
+		return err
+	}
+	err = svc.repo.SetQuotationStatus(ctx, tx, id, status)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update quotation status", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func quotationExpired(quotation *domain.Quotation) bool {
+	return quotation.ValidUntil != nil && quotation.ValidUntil.String() < helper.Now().Format(domain.DateLayout)
+}
+
+// ConvertQuotation turns every line of an accepted quotation into an order at
+// the quoted price. The delivery fee is charged once, on the first order, and
+// the zone minimum applies to the quotation total rather than to each line.
+func (svc *ServiceImpl) ConvertQuotation(ctx context.Context, id string) (data []*domain.Orders, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	quotation, err := svc.repo.LockQuotation(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+		return nil, err
+	}
+	if quotation.Status != domain.QuotationStatusAccepted {
+		err = validationError("hanya penawaran yang sudah disetujui yang dapat dijadikan pesanan")
+		return nil, err
+	}
+	if quotationExpired(quotation) {
+		err = validationError("penawaran sudah kedaluwarsa sejak %s", quotation.ValidUntil)
+		return nil, err
+	}
+	err = svc.checkOrderSchedule(ctx, *quotation.DeliveryDate)
+	if err != nil {
+		logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+		return nil, err
+	}
+
+	area := &domain.Orders{Kecamatan: quotation.Kecamatan, Desa: quotation.Desa}
+	for _, item := range quotation.Items {
+		area.Subtotal += item.UnitPrice * float64(item.Quantity)
+	}
+	if err = svc.normaliseAddress(area); err != nil {
+		return nil, err
+	}
+	if err = svc.applyDeliveryZone(ctx, tx, area); err != nil {
+		return nil, err
+	}
+
+	deliveryFee := quotation.DeliveryFee
+	for _, item := range quotation.Items {
+		order := &domain.Orders{
+			ProductId:    item.ProductId,
+			Variant:      item.Variant,
+			Name:         quotation.Name,
+			Phone:        quotation.Phone,
+			Alamat:       quotation.Alamat,
+			Kecamatan:    quotation.Kecamatan,
+			Desa:         quotation.Desa,
+			Username:     quotation.Username,
+			Quantity:     item.Quantity,
+			DeliveryDate: quotation.DeliveryDate,
+			DeliverySlot: quotation.DeliverySlot,
+		}
+		err = svc.normaliseAddress(order)
+		if err != nil {
+			logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+			return nil, err
+		}
+		err = svc.createOrder(ctx, tx, order, &quotedPrice{UnitPrice: item.UnitPrice, DeliveryFee: deliveryFee})
+		if err != nil {
+			logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+			return nil, err
+		}
+		deliveryFee = 0
+		err = svc.repo.SetQuotationItemOrder(ctx, tx, item.Id, order.Id)
+		if err != nil {
+			logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+			return nil, err
+		}
+		data = append(data, order)
+	}
+	err = svc.repo.SetQuotationStatus(ctx, tx, quotation.Id, domain.QuotationStatusConverted)
+	if err != nil {
+		logger.GetLogger("service-log").Log("convert quotation", "error", err.Error())
+		return nil, err
+	}
+	return data, nil
+}
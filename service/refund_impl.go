@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func isRefundStatus(status string) bool {
+	return status == domain.OrderStatusPartiallyRefunded || status == domain.OrderStatusRefunded
+}
+
+// RequestRefund records a refund against one payment of a cancelled order.
+// Nothing is given back until the refund is approved, but the amount is held
+// so the same money cannot be refunded twice.
+func (svc *ServiceImpl) RequestRefund(ctx context.Context, paymentId string, refund *domain.Refund) (data *domain.Refund, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("request refund", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	payment, err := svc.repo.GetPaymentById(ctx, tx, paymentId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("request refund", "error", err.Error())
+		return nil, err
+	}
+	if payment.VoidedAt != nil {
+		err = errors.New("pembayaran sudah dibatalkan")
+		return nil, err
+	}
+	if payment.Method == domain.PaymentMethodCreditNote && refund.Method != domain.RefundMethodCreditNote {
+		err = errors.New("pembayaran dengan nota kredit hanya bisa dikembalikan sebagai nota kredit")
+		return nil, err
+	}
+	order, err := svc.repo.LockOrderById(ctx, tx, payment.OrderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("request refund", "error", err.Error())
+		return nil, err
+	}
+	if !slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		err = errors.New("batalkan pesanan sebelum mengajukan refund")
+		return nil, err
+	}
+	refunded, err := svc.repo.SumPaymentRefunds(ctx, tx, payment.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("request refund", "error", err.Error())
+		return nil, err
+	}
+	if refund.Amount > payment.Amount-refunded {
+		err = fmt.Errorf("refund melebihi sisa pembayaran yang bisa dikembalikan (%s)", helper.FormatRupiah(payment.Amount-refunded))
+		return nil, err
+	}
+	refund.Id = uuid.New().String()
+	refund.OrderId = order.Id
+	refund.PaymentId = payment.Id
+	refund.Status = domain.RefundStatusPending
+	err = svc.repo.AddRefund(ctx, tx, refund)
+	if err != nil {
+		logger.GetLogger("service-log").Log("request refund", "error", err.Error())
+		return nil, err
+	}
+	return refund, nil
+}
+
+func (svc *ServiceImpl) GetRefunds(ctx context.Context, status string) ([]*domain.Refund, error) {
+	if status == "" {
+		status = domain.RefundStatusPending
+	}
+	refunds, err := svc.repo.GetRefunds(ctx, svc.db, status)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get refunds", "error", err.Error())
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (svc *ServiceImpl) GetOrderRefunds(ctx context.Context, orderId string) ([]*domain.Refund, error) {
+	refunds, err := svc.repo.GetRefundsByOrder(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order refunds", "error", err.Error())
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// ApproveRefund settles a pending refund. A refund by credit note issues the
+// credit note to the customer who placed the order. The order then becomes
+// refunded once everything paid has been given back, otherwise partially
+// refunded.
+func (svc *ServiceImpl) ApproveRefund(ctx context.Context, id string, reviewer string) (data *domain.Refund, err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	refund, order, err := svc.lockPendingRefund(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+		return nil, err
+	}
+	if refund.Method == domain.RefundMethodCreditNote {
+		note := &domain.CreditNote{
+			Id:       uuid.New().String(),
+			Username: order.Username,
+			OrderId:  order.Id,
+			Amount:   refund.Amount,
+			Balance:  refund.Amount,
+			IssuedBy: reviewer,
+		}
+		err = svc.repo.AddCreditNote(ctx, tx, note)
+		if err != nil {
+			logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+			return nil, err
+		}
+		refund.CreditNoteId = note.Id
+	}
+	refund.Status = domain.RefundStatusApproved
+	refund.ReviewedBy = reviewer
+	err = svc.repo.ReviewRefund(ctx, tx, refund)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+		return nil, err
+	}
+	paid, err := svc.repo.SumPayments(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+		return nil, err
+	}
+	refunded, err := svc.repo.SumApprovedRefunds(ctx, tx, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+		return nil, err
+	}
+	status := domain.OrderStatusPartiallyRefunded
+	if refunded >= paid {
+		status = domain.OrderStatusRefunded
+	}
+	err = svc.changeOrderStatus(ctx, tx, order, status, reviewer)
+	if err != nil {
+		logger.GetLogger("service-log").Log("approve refund", "error", err.Error())
+		return nil, err
+	}
+	return refund, nil
+}
+
+func (svc *ServiceImpl) RejectRefund(ctx context.Context, id string, reviewer string, reason string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject refund", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	refund, _, err := svc.lockPendingRefund(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject refund", "error", err.Error())
+		return err
+	}
+	refund.Status = domain.RefundStatusRejected
+	refund.ReviewedBy = reviewer
+	refund.RejectReason = reason
+	err = svc.repo.ReviewRefund(ctx, tx, refund)
+	if err != nil {
+		logger.GetLogger("service-log").Log("reject refund", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) lockPendingRefund(ctx context.Context, tx *sql.Tx, id string) (*domain.Refund, *domain.Orders, error) {
+	refund, err := svc.repo.GetRefundById(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if refund.Status != domain.RefundStatusPending {
+		return nil, nil, errors.New("refund sudah diproses")
+	}
+	order, err := svc.repo.LockOrderById(ctx, tx, refund.OrderId)
+	if err != nil {
+		return nil, nil, err
+	}
+	return refund, order, nil
+}
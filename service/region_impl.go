@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"strings"
+)
+
+func (svc *ServiceImpl) GetRegions(ctx context.Context, parentCode string) ([]*domain.Region, error) {
+	if parentCode != "" {
+		if _, ok := svc.regions.Get(parentCode); !ok {
+			return nil, fmt.Errorf("wilayah %s tidak ditemukan", parentCode)
+		}
+	}
+	result := svc.regions.Children(parentCode)
+	if result == nil {
+		result = []*domain.Region{}
+	}
+	return result, nil
+}
+
+func (svc *ServiceImpl) GetRegion(ctx context.Context, code string) (*domain.Region, error) {
+	result, ok := svc.regions.Get(code)
+	if !ok {
+		return nil, fmt.Errorf("wilayah %s tidak ditemukan", code)
+	}
+	return result, nil
+}
+
+func (svc *ServiceImpl) SearchRegions(ctx context.Context, query string, level string, parentCode string, limit int) ([]*domain.Region, error) {
+	if len(query) < 2 {
+		return nil, errors.New("kata kunci minimal 2 huruf")
+	}
+	result := svc.regions.Search(query, level, parentCode, limit)
+	if result == nil {
+		result = []*domain.Region{}
+	}
+	return result, nil
+}
+
+// normaliseAddress replaces the free-text kecamatan and desa with the names
+// from the region dataset and stores their codes on the order.
+func (svc *ServiceImpl) normaliseAddress(order *domain.Orders) error {
+	kecamatan, desa, err := svc.regions.ResolveAddress(order.Kecamatan, order.Desa)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	order.Kecamatan = kecamatan.Name
+	order.KecamatanCode = kecamatan.Code
+	if desa != nil {
+		order.Desa = desa.Name
+		order.DesaCode = desa.Code
+	}
+	return nil
+}
+
+// normaliseZone spells the zone area the way orders are stored. Where the
+// dataset lists no desa for the kecamatan the desa is kept as typed, so a
+// zone meant for one desa never widens to the whole kecamatan.
+func (svc *ServiceImpl) normaliseZone(zone *domain.DeliveryZone) error {
+	kecamatan, desa, err := svc.regions.ResolveAddress(zone.Kecamatan, zone.Desa)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	zone.Kecamatan = kecamatan.Name
+	zone.Desa = strings.TrimSpace(zone.Desa)
+	if desa != nil {
+		zone.Desa = desa.Name
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) normaliseQuotation(quotation *domain.Quotation) error {
+	phone, err := helper.NormalizePhone(quotation.Phone)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	quotation.Phone = phone
+	kecamatan, desa, err := svc.regions.ResolveAddress(quotation.Kecamatan, quotation.Desa)
+	if err != nil {
+		return &ValidationError{Message: err.Error()}
+	}
+	quotation.Kecamatan = kecamatan.Name
+	if desa != nil {
+		quotation.Desa = desa.Name
+	}
+	return nil
+}
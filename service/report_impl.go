@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+func (svc *ServiceImpl) GetProductionSheet(ctx context.Context, date domain.Date) (*domain.ProductionSheet, error) {
+	items, err := svc.repo.GetProductionItems(ctx, svc.db, date, domain.ProductionStatuses)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get production sheet", "error", err.Error())
+		return nil, err
+	}
+	sheet := &domain.ProductionSheet{
+		Date:  date,
+		Items: items,
+	}
+	var orderIds []string
+	for _, item := range items {
+		sheet.TotalPortions += item.Quantity
+		sheet.TotalOrders += len(item.Orders)
+		for _, order := range item.Orders {
+			orderIds = append(orderIds, order.OrderId)
+		}
+	}
+	notes, err := svc.orderNoteLines(ctx, orderIds)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get production sheet", "error", err.Error())
+		return nil, err
+	}
+	for _, item := range items {
+		for _, order := range item.Orders {
+			order.Notes = notes[order.OrderId]
+		}
+	}
+	if sheet.Items == nil {
+		sheet.Items = []*domain.ProductionItem{}
+	}
+	return sheet, nil
+}
+
+func (svc *ServiceImpl) GetDeliveryManifest(ctx context.Context, date domain.Date) (*domain.DeliveryManifest, error) {
+	orders, err := svc.repo.GetManifestOrders(ctx, svc.db, date, domain.ManifestStatuses)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get delivery manifest", "error", err.Error())
+		return nil, err
+	}
+	orderIds := make([]string, len(orders))
+	for i, order := range orders {
+		orderIds[i] = order.Id
+	}
+	notes, err := svc.orderNoteLines(ctx, orderIds)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get delivery manifest", "error", err.Error())
+		return nil, err
+	}
+	manifest := &domain.DeliveryManifest{
+		Date:  date,
+		Areas: []*domain.ManifestArea{},
+	}
+	var area *domain.ManifestArea
+	for _, order := range orders {
+		if area == nil || area.Kecamatan != order.Kecamatan || area.Desa != order.Desa {
+			area = &domain.ManifestArea{
+				Kecamatan: order.Kecamatan,
+				Desa:      order.Desa,
+			}
+			manifest.Areas = append(manifest.Areas, area)
+		}
+		stop := &domain.ManifestStop{
+			OrderId:         order.Id,
+			DeliverySlot:    order.DeliverySlot,
+			Name:            order.Name,
+			Phone:           order.Phone,
+			Alamat:          order.Alamat,
+			ProductName:     order.ProductName,
+			Variant:         order.Variant,
+			Quantity:        order.Quantity,
+			AmountToCollect: max(order.Total-order.AmountPaid, 0),
+			CustomerNote:    order.CustomerNote,
+			Notes:           notes[order.Id],
+		}
+		area.Stops = append(area.Stops, stop)
+		area.TotalToCollect += stop.AmountToCollect
+		manifest.TotalToCollect += stop.AmountToCollect
+		manifest.TotalOrders++
+	}
+	return manifest, nil
+}
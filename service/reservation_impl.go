@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"slices"
+	"time"
+)
+
+var orderPaymentMethods = []string{"", domain.PaymentMethodCash, domain.PaymentMethodTransfer, domain.PaymentMethodQris, domain.PaymentMethodGateway}
+
+func (svc *ServiceImpl) GetReservationPolicies(ctx context.Context) ([]*domain.ReservationPolicy, error) {
+	policies, err := svc.repo.GetReservationPolicies(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get reservation policies", "error", err.Error())
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (svc *ServiceImpl) SaveReservationPolicy(ctx context.Context, policy *domain.ReservationPolicy) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("save reservation policy", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.SaveReservationPolicy(ctx, tx, policy)
+	if err != nil {
+		logger.GetLogger("service-log").Log("save reservation policy", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// reserveStock sets how long an unpaid order may hold its stock, based on
+// the payment method the customer picked.
+func (svc *ServiceImpl) reserveStock(ctx context.Context, tx *sql.Tx, order *domain.Orders) error {
+	if !slices.Contains(orderPaymentMethods, order.PaymentMethod) {
+		return fmt.Errorf("metode pembayaran %s tidak dikenal", order.PaymentMethod)
+	}
+	order.ReservedUntil = nil
+	ttl, err := svc.repo.GetReservationTTL(ctx, tx, order.PaymentMethod)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		until := time.Now().Add(time.Duration(ttl) * time.Minute)
+		order.ReservedUntil = &until
+	}
+	return nil
+}
+
+// ReleaseExpiredReservations cancels pending orders whose reservation ran out
+// without any payment and puts their stock back. An order that cannot be
+// released is logged and skipped, and is tried again on the next run.
+func (svc *ServiceImpl) ReleaseExpiredReservations(ctx context.Context) error {
+	now := time.Now()
+	failed := 0
+	after := ""
+	for {
+		ids, err := svc.repo.GetExpiredReservations(ctx, svc.db, now, after, 100)
+		if err != nil {
+			logger.GetLogger("service-log").Log("release reservations", "error", err.Error())
+			return err
+		}
+		for _, id := range ids {
+			if err := svc.releaseReservation(ctx, id); err != nil {
+				logger.GetLogger("service-log").Log("release reservations", "error", fmt.Sprintf("order %s: %s", id, err.Error()))
+				failed++
+			}
+		}
+		if len(ids) < 100 {
+			break
+		}
+		after = ids[len(ids)-1]
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d reservasi gagal dilepas", failed)
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) releaseReservation(ctx context.Context, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if order.Status != domain.OrderStatusPending || order.ReservedUntil == nil || order.ReservedUntil.After(time.Now()) {
+		return nil
+	}
+	paid, err := svc.repo.SumPayments(ctx, tx, order.Id)
+	if err != nil {
+		return err
+	}
+	if paid > 0 {
+		return svc.repo.ClearReservation(ctx, tx, order.Id)
+	}
+	return svc.cancelOrder(ctx, tx, order, "")
+}
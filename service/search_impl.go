@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultOrderPageSize = 50
+	maxOrderPageSize     = 200
+)
+
+// ErrInvalidCursor is returned for a pagination cursor that is malformed or
+// was issued for a different sort order.
+var ErrInvalidCursor = errors.New("cursor tidak valid")
+
+type orderCursor struct {
+	SortBy string `json:"s"`
+	Value  string `json:"v"`
+	Id     string `json:"id"`
+}
+
+// SearchOrders returns one page of orders matching the filter together with
+// the total number of matches and the cursor of the next page, if any.
+func (svc *ServiceImpl) SearchOrders(ctx context.Context, filter *domain.OrderFilter, cursor string) (*domain.OrderPage, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = domain.OrderSortCreatedAt
+		filter.Descending = true
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultOrderPageSize
+	}
+	filter.Limit = min(filter.Limit, maxOrderPageSize)
+	if cursor != "" {
+		after, err := decodeOrderCursor(filter.SortBy, cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	orders, err := svc.repo.SearchOrders(ctx, svc.db, filter)
+	filter.Limit = limit
+	if err != nil {
+		logger.GetLogger("service-log").Log("search orders", "error", err.Error())
+		return nil, err
+	}
+	page := &domain.OrderPage{Orders: orders}
+	if len(orders) > limit {
+		page.Orders = orders[:limit]
+		page.NextCursor = encodeOrderCursor(filter.SortBy, page.Orders[limit-1])
+	}
+	page.Total, err = svc.repo.CountOrders(ctx, svc.db, filter)
+	if err != nil {
+		logger.GetLogger("service-log").Log("search orders", "error", err.Error())
+		return nil, err
+	}
+	return page, nil
+}
+
+func encodeOrderCursor(sortBy string, order *domain.Orders) string {
+	cursor := orderCursor{SortBy: sortBy, Id: order.Id}
+	switch sortBy {
+	case domain.OrderSortDeliveryDate:
+		cursor.Value = "1000-01-01"
+		if order.DeliveryDate != nil {
+			cursor.Value = order.DeliveryDate.String()
+		}
+	case domain.OrderSortTotal:
+		cursor.Value = strconv.FormatFloat(order.Total, 'f', -1, 64)
+	default:
+		if order.CreatedAt != nil {
+			cursor.Value = order.CreatedAt.Format(time.RFC3339Nano)
+		}
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeOrderCursor(sortBy string, value string) (*domain.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor orderCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.SortBy != sortBy || cursor.Id == "" {
+		return nil, ErrInvalidCursor
+	}
+	after := &domain.OrderCursor{Id: cursor.Id, Value: cursor.Value}
+	switch sortBy {
+	case domain.OrderSortTotal:
+		total, err := strconv.ParseFloat(cursor.Value, 64)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after.Value = total
+	case domain.OrderSortCreatedAt:
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after.Value = createdAt
+	}
+	return after, nil
+}
+
+// ExportOrders feeds every order matching the filter to fn, oldest first
+// unless another sort was asked for.
+func (svc *ServiceImpl) ExportOrders(ctx context.Context, filter *domain.OrderFilter, fn func(*domain.Orders) error) error {
+	if filter.SortBy == "" {
+		filter.SortBy = domain.OrderSortCreatedAt
+	}
+	err := svc.repo.StreamOrders(ctx, svc.db, filter, fn)
+	if err != nil {
+		logger.GetLogger("service-log").Log("export orders", "error", err.Error())
+		return err
+	}
+	return nil
+}
@@ -10,17 +10,94 @@ import (
 type Service interface {
 	Login(ctx context.Context, request *domain.Admin) (*web.AdminResponse, error)
 	AddProduct(ctx context.Context, request *web.Request, file *multipart.FileHeader) (*domain.Domain, error)
-	GetProducts(ctx context.Context) ([]*domain.Domain, error)
+	GetProducts(ctx context.Context) ([]*domain.RatedProduct, error)
 	DeleteProduct(ctx context.Context, id string) error
 	UpdateProduct(ctx context.Context, request *web.Request, id string) (*domain.Domain, error)
-	GetOrders(ctx context.Context) ([]*domain.Orders, error)
 	AddOrders(ctx context.Context, requet *domain.Orders) error
-	UpdateOrder(ctx context.Context, entity *domain.Orders, id string) error
+	UpdateOrder(ctx context.Context, entity *domain.Orders, id string, username string) error
 	DeleteOrder(ctx context.Context, id string) error
 	GetUsers(ctx context.Context) ([]*domain.Users, error)
 	GetUserByUsername(ctx context.Context, username string) (*domain.Users, error)
 	DeleteUserById(ctx context.Context, id string) error
-	GetOrdersByUsername(ctx context.Context, username string) ([]*domain.Orders, error)
-	GetOrderById(ctx context.Context, id string) (*domain.Orders, error)
+	GetOrdersByUsername(ctx context.Context, username string, expand domain.OrderExpand) ([]*domain.Orders, error)
+	GetOrderById(ctx context.Context, id string, expand domain.OrderExpand) (*domain.Orders, error)
 	GetLog(ctx context.Context) ([]*domain.Hit, error)
+	GetDailyCapacities(ctx context.Context) ([]*domain.DailyCapacity, error)
+	SaveDailyCapacity(ctx context.Context, entity *domain.DailyCapacity) error
+	DeleteDailyCapacity(ctx context.Context, category string) error
+	GetProductionSettings(ctx context.Context) (*domain.ProductionSettings, error)
+	UpdateProductionSettings(ctx context.Context, entity *domain.ProductionSettings) error
+	GetBlackoutDates(ctx context.Context) ([]*domain.BlackoutDate, error)
+	AddBlackoutDate(ctx context.Context, entity *domain.BlackoutDate) error
+	DeleteBlackoutDate(ctx context.Context, date domain.Date) error
+	GetProductionSheet(ctx context.Context, date domain.Date) (*domain.ProductionSheet, error)
+	GetDeliveryManifest(ctx context.Context, date domain.Date) (*domain.DeliveryManifest, error)
+	GetDeliveryZones(ctx context.Context) ([]*domain.DeliveryZone, error)
+	AddDeliveryZone(ctx context.Context, entity *domain.DeliveryZone) error
+	UpdateDeliveryZone(ctx context.Context, entity *domain.DeliveryZone, id string) error
+	DeleteDeliveryZone(ctx context.Context, id string) error
+	GetRegions(ctx context.Context, parentCode string) ([]*domain.Region, error)
+	GetRegion(ctx context.Context, code string) (*domain.Region, error)
+	SearchRegions(ctx context.Context, query string, level string, parentCode string, limit int) ([]*domain.Region, error)
+	AddPayment(ctx context.Context, orderId string, payment *domain.Payment) (*domain.Payment, error)
+	GetPaymentSummary(ctx context.Context, orderId string) (*domain.PaymentSummary, error)
+	VoidPayment(ctx context.Context, id string, voidedBy string, reason string) error
+	UploadPaymentProof(ctx context.Context, orderId string, proof *domain.PaymentProof, file *multipart.FileHeader) (*domain.PaymentProof, error)
+	GetPaymentProofs(ctx context.Context, status string) ([]*domain.PaymentProof, error)
+	GetPaymentProofFile(ctx context.Context, id string) (string, error)
+	ApprovePaymentProof(ctx context.Context, id string, reviewer string) (*domain.Payment, error)
+	RejectPaymentProof(ctx context.Context, id string, reviewer string, reason string) error
+	CreatePaymentLink(ctx context.Context, orderId string) (*domain.PaymentLink, error)
+	HandlePaymentNotification(ctx context.Context, body []byte) error
+	GetPaymentLinks(ctx context.Context, status string) ([]*domain.PaymentLink, error)
+	ResolvePaymentLink(ctx context.Context, id string, action string, resolver string) (*domain.PaymentLink, error)
+	GetInvoice(ctx context.Context, orderId string) (*domain.Invoice, error)
+	AddQuotation(ctx context.Context, quotation *domain.Quotation) (*domain.Quotation, error)
+	GetQuotations(ctx context.Context, status string) ([]*domain.Quotation, error)
+	GetQuotation(ctx context.Context, id string, revision int) (*domain.Quotation, error)
+	ReviseQuotation(ctx context.Context, id string, quotation *domain.Quotation) (*domain.Quotation, error)
+	UpdateQuotationStatus(ctx context.Context, id string, status string) error
+	ConvertQuotation(ctx context.Context, id string) ([]*domain.Orders, error)
+	GetVouchers(ctx context.Context) ([]*domain.Voucher, error)
+	AddVoucher(ctx context.Context, voucher *domain.Voucher) (*domain.Voucher, error)
+	UpdateVoucher(ctx context.Context, voucher *domain.Voucher, id string) error
+	DeactivateVoucher(ctx context.Context, id string) error
+	GetDiscountRules(ctx context.Context) ([]*domain.DiscountRule, error)
+	AddDiscountRule(ctx context.Context, rule *domain.DiscountRule) (*domain.DiscountRule, error)
+	UpdateDiscountRule(ctx context.Context, rule *domain.DiscountRule, id string) error
+	DeleteDiscountRule(ctx context.Context, id string) error
+	AddSubscription(ctx context.Context, subscription *domain.Subscription) (*domain.Subscription, error)
+	GetSubscriptions(ctx context.Context, status string) ([]*domain.Subscription, error)
+	GetSubscription(ctx context.Context, id string) (*domain.Subscription, error)
+	PauseSubscription(ctx context.Context, id string, until *domain.Date) error
+	ResumeSubscription(ctx context.Context, id string) error
+	CancelSubscription(ctx context.Context, id string) error
+	SkipSubscriptionDate(ctx context.Context, id string, date domain.Date) error
+	UnskipSubscriptionDate(ctx context.Context, id string, date domain.Date) error
+	MaterialiseSubscriptions(ctx context.Context) error
+	GetReservationPolicies(ctx context.Context) ([]*domain.ReservationPolicy, error)
+	SaveReservationPolicy(ctx context.Context, policy *domain.ReservationPolicy) error
+	ReleaseExpiredReservations(ctx context.Context) error
+	AddOrdersIdempotent(ctx context.Context, key *domain.IdempotencyKey, orderDetails *domain.Orders) (*domain.IdempotencyKey, error)
+	PurgeIdempotencyKeys(ctx context.Context) error
+	EditOrder(ctx context.Context, request *web.EditOrderRequest, id string, username string) (*domain.Orders, error)
+	GetOrderHistory(ctx context.Context, orderId string) ([]*domain.OrderHistory, error)
+	SearchOrders(ctx context.Context, filter *domain.OrderFilter, cursor string) (*domain.OrderPage, error)
+	ExportOrders(ctx context.Context, filter *domain.OrderFilter, fn func(*domain.Orders) error) error
+	BulkUpdateOrders(ctx context.Context, request *web.BulkOrderRequest, filter *domain.OrderFilter, username string) (*domain.BulkOrderReport, error)
+	AddOrderNote(ctx context.Context, orderId string, request *web.OrderNoteRequest, author string) (*domain.OrderNote, error)
+	GetOrderNotes(ctx context.Context, orderId string) ([]*domain.OrderNote, error)
+	GetOrderTracking(ctx context.Context, token string) (*domain.OrderTracking, error)
+	GetTrackingToken(ctx context.Context, orderId string) (string, error)
+	RequestRefund(ctx context.Context, paymentId string, refund *domain.Refund) (*domain.Refund, error)
+	GetRefunds(ctx context.Context, status string) ([]*domain.Refund, error)
+	GetOrderRefunds(ctx context.Context, orderId string) ([]*domain.Refund, error)
+	ApproveRefund(ctx context.Context, id string, reviewer string) (*domain.Refund, error)
+	RejectRefund(ctx context.Context, id string, reviewer string, reason string) error
+	GetCreditNotes(ctx context.Context, username string) ([]*domain.CreditNote, error)
+	ApplyCreditNote(ctx context.Context, orderId string, request *web.ApplyCreditNoteRequest, receivedBy string) (*domain.Payment, error)
+	SubmitFeedback(ctx context.Context, token string, request *web.FeedbackRequest) (*domain.Feedback, error)
+	GetFeedback(ctx context.Context, status string, productId string) ([]*domain.Feedback, error)
+	ModerateFeedback(ctx context.Context, id string, status string, moderator string) error
+	AssignOrderCodes(ctx context.Context) error
 }
@@ -3,28 +3,38 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"khaira-admin/domain"
+	"khaira-admin/gateway"
 	"khaira-admin/helper"
 	"khaira-admin/logger"
+	"khaira-admin/region"
 	"khaira-admin/repository"
 	"khaira-admin/web"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
 type ServiceImpl struct {
-	repo repository.Repository
-	db   *sql.DB
+	repo    repository.Repository
+	db      *sql.DB
+	regions *region.Index
+	gateway gateway.Provider
 }
 
-func NewServiceImpl(repo repository.Repository, db *sql.DB) Service {
+func NewServiceImpl(repo repository.Repository, db *sql.DB, regions *region.Index, provider gateway.Provider) Service {
 	return &ServiceImpl{
-		repo: repo,
-		db:   db,
+		repo:    repo,
+		db:      db,
+		regions: regions,
+		gateway: provider,
 	}
 }
 
@@ -55,6 +65,9 @@ func (svc *ServiceImpl) AddProduct(ctx context.Context, request *web.Request, fi
 		return nil, err
 	}
 	request.ImageMetadata = filename
+	if request.Category == "" {
+		request.Category = domain.DefaultCategory
+	}
 	now := time.Now()
 	request.CreatedAt = &now
 	tx, err := svc.db.Begin()
@@ -73,13 +86,18 @@ func (svc *ServiceImpl) AddProduct(ctx context.Context, request *web.Request, fi
 	return data, nil
 }
 
-func (svc *ServiceImpl) GetProducts(ctx context.Context) (data []*domain.Domain, err error) {
+func (svc *ServiceImpl) GetProducts(ctx context.Context) (data []*domain.RatedProduct, err error) {
 	products, err := svc.repo.GetProducts(ctx, svc.db)
 	if err != nil {
 		logger.GetLogger("service-log").Log("get products", "error", err.Error())
 		return nil, err
 	}
-	return products, nil
+	data, err = svc.rateProducts(ctx, products)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get products", "error", err.Error())
+		return nil, err
+	}
+	return data, nil
 }
 
 func (svc *ServiceImpl) DeleteProduct(ctx context.Context, id string) error {
@@ -114,23 +132,36 @@ func (svc *ServiceImpl) UpdateProduct(ctx context.Context, request *web.Request,
 	return data, nil
 }
 
-func (svc *ServiceImpl) GetOrders(ctx context.Context) (orders []*domain.Orders, err error) {
-	orders, err = svc.repo.GetOrders(ctx, svc.db)
-	if err != nil {
-		logger.GetLogger("service-log").Log("get orders", "error", err.Error())
-		return nil, err
-	}
-	return orders, nil
-}
-
-func (svc *ServiceImpl) UpdateOrder(ctx context.Context, entity *domain.Orders, id string) (err error) {
+func (svc *ServiceImpl) UpdateOrder(ctx context.Context, entity *domain.Orders, id string, username string) (err error) {
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("update order", "error", err.Error())
 		return err
 	}
 	defer helper.WithTransaction(tx, &err)
-	err = svc.repo.UpdateOrder(ctx, tx, entity, id)
+	order, err := svc.repo.LockOrderById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update order", "error", err.Error())
+		return err
+	}
+	if isRefundStatus(order.Status) || isRefundStatus(entity.Status) {
+		err = errors.New("status refund hanya berubah lewat persetujuan refund")
+		return err
+	}
+	if order.Status == entity.Status {
+		return nil
+	}
+	// A cancelled order has already given back its stock and voucher, so it
+	// cannot be reopened without taking them again.
+	if slices.Contains(domain.CancelledOrderStatuses, order.Status) {
+		err = errors.New("pesanan sudah dibatalkan")
+		return err
+	}
+	if entity.Status == domain.OrderStatusCancelled {
+		err = svc.cancelOrder(ctx, tx, order, username)
+	} else {
+		err = svc.changeOrderStatus(ctx, tx, order, entity.Status, username)
+	}
 	if err != nil {
 		logger.GetLogger("service-log").Log("update order", "error", err.Error())
 		return err
@@ -145,6 +176,15 @@ func (svc *ServiceImpl) DeleteOrder(ctx context.Context, id string) error {
 		return err
 	}
 	defer helper.WithTransaction(tx, &err)
+	order, err := svc.repo.LockOrderById(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete order", "error", err.Error())
+		return err
+	}
+	if order.InvoiceNumber != "" {
+		err = validationError("pesanan %s sudah memiliki nomor invoice, batalkan pesanan alih-alih menghapusnya", order.InvoiceNumber)
+		return err
+	}
 	err = svc.repo.DeleteOrder(ctx, tx, id)
 	if err != nil {
 		logger.GetLogger("service-log").Log("delete order", "error", err.Error())
@@ -171,17 +211,33 @@ func (svc *ServiceImpl) GetUserByUsername(ctx context.Context, username string)
 	return result, nil
 }
 
-func (svc *ServiceImpl) GetOrdersByUsername(ctx context.Context, username string) ([]*domain.Orders, error) {
+func (svc *ServiceImpl) GetOrdersByUsername(ctx context.Context, username string, expand domain.OrderExpand) ([]*domain.Orders, error) {
 	result, err := svc.repo.GetOrderByUsername(ctx, svc.db, username)
 	if err != nil {
 		logger.GetLogger("service-log").Log("get orders by username", "error", err.Error())
 		return nil, err
 	}
+	err = svc.expandOrders(ctx, result, expand)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get orders by username", "error", err.Error())
+		return nil, err
+	}
 	return result, nil
 }
 
-func (svc *ServiceImpl) GetOrderById(ctx context.Context, id string) (*domain.Orders, error) {
-	result, err := svc.repo.GetOrderById(ctx, svc.db, id)
+// GetOrderById also accepts an order code such as KH-7Q3F9 in place of the id.
+func (svc *ServiceImpl) GetOrderById(ctx context.Context, id string, expand domain.OrderExpand) (result *domain.Orders, err error) {
+	if code, ok := domain.ParseOrderCode(id); ok {
+		result, err = svc.repo.GetOrderByCode(ctx, svc.db, code)
+	} else {
+		result, err = svc.repo.GetOrderById(ctx, svc.db, id)
+	}
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order by id", "error", err.Error())
+		return nil, err
+	}
+	expand.Notes = true
+	err = svc.expandOrders(ctx, []*domain.Orders{result}, expand)
 	if err != nil {
 		logger.GetLogger("service-log").Log("get order by id", "error", err.Error())
 		return nil, err
@@ -200,6 +256,11 @@ func (svc *ServiceImpl) GetLog(ctx context.Context) ([]*domain.Hit, error) {
 }
 
 func (svc *ServiceImpl) AddOrders(ctx context.Context, orderDetails *domain.Orders) error {
+	err := svc.prepareOrder(ctx, orderDetails)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add order", "error", err.Error())
+		return err
+	}
 	tx, err := svc.db.Begin()
 	if err != nil {
 		logger.GetLogger("service-log").Log("add order", "error", err.Error())
@@ -212,8 +273,7 @@ func (svc *ServiceImpl) AddOrders(ctx context.Context, orderDetails *domain.Orde
 			tx.Commit()
 		}
 	}()
-	id := uuid.New()
-	err = svc.repo.AddOrders(ctx, tx, orderDetails, id)
+	err = svc.placeOrder(ctx, tx, orderDetails)
 	if err != nil {
 		logger.GetLogger("service-log").Log("add order", "error", err.Error())
 		return err
@@ -221,6 +281,110 @@ func (svc *ServiceImpl) AddOrders(ctx context.Context, orderDetails *domain.Orde
 	return nil
 }
 
+// prepareOrder runs the checks that do not need a transaction before an order
+// placed by a customer is created.
+func (svc *ServiceImpl) prepareOrder(ctx context.Context, orderDetails *domain.Orders) error {
+	if orderDetails.Quantity <= 0 {
+		return errors.New("jumlah pesanan harus lebih dari 0")
+	}
+	if orderDetails.DeliveryDate == nil {
+		return errors.New("tanggal pengiriman wajib diisi")
+	}
+	phone, err := helper.NormalizePhone(orderDetails.Phone)
+	if err != nil {
+		return err
+	}
+	orderDetails.Phone = phone
+	orderDetails.CustomerNote = strings.TrimSpace(orderDetails.CustomerNote)
+	if utf8.RuneCountInString(orderDetails.CustomerNote) > 500 {
+		return errors.New("catatan pesanan maksimal 500 karakter")
+	}
+	if err := svc.normaliseAddress(orderDetails); err != nil {
+		return err
+	}
+	return svc.checkOrderSchedule(ctx, *orderDetails.DeliveryDate)
+}
+
+func (svc *ServiceImpl) placeOrder(ctx context.Context, tx *sql.Tx, orderDetails *domain.Orders) error {
+	if err := svc.reserveStock(ctx, tx, orderDetails); err != nil {
+		return err
+	}
+	return svc.createOrder(ctx, tx, orderDetails, nil)
+}
+
+// quotedPrice replaces the catalogue price and zone fee for orders that come
+// from a negotiated quotation. Quoted orders get no automatic discounts, and
+// their delivery zone is checked once for the whole quotation by the caller
+// rather than per line.
+type quotedPrice struct {
+	UnitPrice   float64
+	DeliveryFee float64
+}
+
+// createOrder prices, checks and stores a single order inside tx. Callers
+// validate the request, normalise the address and check the schedule first.
+func (svc *ServiceImpl) createOrder(ctx context.Context, tx *sql.Tx, orderDetails *domain.Orders, quote *quotedPrice) error {
+	product, err := svc.repo.GetProductById(ctx, tx, orderDetails.ProductId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return validationError("produk %s tidak ditemukan", orderDetails.ProductId)
+		}
+		return err
+	}
+	orderDetails.ProductName = product.Name
+	orderDetails.Subtotal = float64(product.Price * orderDetails.Quantity)
+	if quote != nil {
+		orderDetails.Subtotal = quote.UnitPrice * float64(orderDetails.Quantity)
+	}
+	if quote == nil {
+		err = svc.applyDeliveryZone(ctx, tx, orderDetails)
+		if err != nil {
+			return err
+		}
+	}
+	var voucher *domain.Voucher
+	var voucherDiscount float64
+	if quote != nil {
+		orderDetails.DeliveryFee = quote.DeliveryFee
+		orderDetails.Discount = 0
+		orderDetails.VoucherCode = ""
+	} else {
+		voucher, voucherDiscount, err = svc.applyDiscounts(ctx, tx, orderDetails, product)
+		if err != nil {
+			return err
+		}
+	}
+	orderDetails.Total = orderDetails.Subtotal - orderDetails.Discount + orderDetails.DeliveryFee
+	err = svc.checkProductionCapacity(ctx, tx, *orderDetails.DeliveryDate, product.Category, orderDetails.Quantity)
+	if err != nil {
+		return err
+	}
+	orderDetails.InvoiceNumber, err = svc.nextInvoiceNumber(ctx, tx, helper.Now())
+	if err != nil {
+		return err
+	}
+	orderDetails.TrackingToken, err = helper.NewTrackingToken()
+	if err != nil {
+		return err
+	}
+	id := uuid.New()
+	err = svc.withOrderCode(ctx, tx, func(code string) error {
+		orderDetails.Code = code
+		return svc.repo.AddOrders(ctx, tx, orderDetails, id)
+	})
+	if err != nil {
+		return err
+	}
+	orderDetails.Id = id.String()
+	if voucher != nil {
+		err = svc.repo.RedeemVoucher(ctx, tx, voucher.Id, orderDetails.Id, orderDetails.Username, voucherDiscount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (svc *ServiceImpl) DeleteUserById(ctx context.Context, id string) error {
 	err := svc.repo.DeleteUserById(ctx, svc.db, id)
 	if err != nil {
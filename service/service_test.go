@@ -0,0 +1,1832 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/gateway"
+	"khaira-admin/helper"
+	"khaira-admin/region"
+	"khaira-admin/repository"
+	"khaira-admin/web"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var orderColumns = []string{"id", "code", "invoice_number", "product_id", "product_name", "variant", "username", "name", "phone", "alamat", "kecamatan", "kecamatan_code",
+	"desa", "desa_code", "quantity", "subtotal", "discount", "voucher_code", "delivery_fee", "total", "amount_paid", "payment_method", "reserved_until",
+	"status", "delivery_date", "delivery_slot", "courier", "customer_note", "created_at", "modified_at"}
+
+// orderRow returns a row for orderColumns describing a ten portion order of
+// P001 that has been paid amountPaid so far.
+func orderRow(mock sqlmock.Sqlmock, id string, status string, total float64, amountPaid float64) *sqlmock.Rows {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	values := []driver.Value{id, "KH-7Q3F9", "", "P001", "Nasi Box", "", "user1", "Siti", "+6281234567890",
+		"Jl. Mawar 1", "Cibinong", "3201010", "Pakansari", "3201010001", 10, total, 0.0, "", 0.0, total, amountPaid, "transfer", nil,
+		status, nil, "siang", "", "", createdAt, createdAt}
+	return mock.NewRows(orderColumns).AddRow(values...)
+}
+
+func TestUpdateOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name:   "cancel gives back stock and voucher",
+			status: domain.OrderStatusCancelled,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusConfirmed, 250000, 0))
+				mock.ExpectExec(`UPDATE orders SET status = \? WHERE id = \?`).
+					WithArgs(domain.OrderStatusCancelled, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO order_history`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE products SET stock = stock \+ \? WHERE id = \?`).
+					WithArgs(10, "P001").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE vouchers v JOIN voucher_redemptions r`).
+					WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM voucher_redemptions WHERE order_id = \?`).
+					WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE orders SET reserved_until = NULL WHERE id = \?`).
+					WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "other status only changes the status",
+			status: domain.OrderStatusDelivered,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusConfirmed, 250000, 0))
+				mock.ExpectExec(`UPDATE orders SET status = \? WHERE id = \?`).
+					WithArgs(domain.OrderStatusDelivered, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO order_history`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "cancelled order cannot be reopened",
+			status: domain.OrderStatusConfirmed,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+		{
+			name:   "cancelling twice does not give back stock again",
+			status: domain.OrderStatusCancelled,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 0))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			err = svc.UpdateOrder(context.Background(), &domain.Orders{Status: tt.status}, "o-1", "admin")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+		invalid     bool
+	}{
+		{
+			name: "order without invoice number is deleted",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusPending, 150000, 0))
+				mock.ExpectExec(`(?i)delete from orders where id = \? and invoice_number is null`).
+					WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "invoiced order must be cancelled instead",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(mock.NewRows(orderColumns).AddRow("o-1", "KH-7Q3F9", "INV/2026/10/0001", "P001", "Nasi Box", "", "user1", "Siti", "+6281234567890",
+						"Jl. Mawar 1", "Cibinong", "3201010", "Pakansari", "3201010001", 10, 150000.0, 0.0, "", 0.0, 150000.0, 0.0, "transfer", nil,
+						domain.OrderStatusPending, nil, "siang", "", "", createdAt, createdAt))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+			invalid:     true,
+		},
+		{
+			name: "order not found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			err = svc.DeleteOrder(context.Background(), "o-1")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				var invalid *ValidationError
+				assert.Equal(t, tt.invalid, errors.As(err, &invalid))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+var paymentColumns = []string{"id", "order_id", "method", "amount", "reference", "proof", "received_by", "paid_at", "voided_at", "voided_by", "void_reason", "created_at"}
+
+func paymentRow(mock sqlmock.Sqlmock, id string, method string, amount float64, reference string, voidedAt *time.Time) *sqlmock.Rows {
+	paidAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	return mock.NewRows(paymentColumns).AddRow(id, "o-1", method, amount, reference, "", "admin", paidAt, voidedAt, "", "", paidAt)
+}
+
+func TestRejectPaymentProof(t *testing.T) {
+	proofColumns := []string{"id", "order_id", "amount", "bank_name", "account_name", "image", "status", "uploaded_by", "reviewed_by", "reviewed_at", "reject_reason", "payment_id", "created_at"}
+	createdAt := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	approvedProof := func(mock sqlmock.Sqlmock) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`(?i)select .* from payment_proofs where id = \? for update`).
+			WithArgs("pp-1").
+			WillReturnRows(mock.NewRows(proofColumns).AddRow("pp-1", "o-1", 250000.0, "BCA", "Siti", "proof.jpg", domain.ProofStatusApproved,
+				"user1", "admin", createdAt, "", "pay-1", createdAt))
+		mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+			WithArgs("o-1").
+			WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 250000))
+	}
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "approved proof voids its payment",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				approvedProof(mock)
+				mock.ExpectQuery(`(?i)select .* from payments where id = \? for update`).
+					WithArgs("pay-1").
+					WillReturnRows(paymentRow(mock, "pay-1", domain.PaymentMethodTransfer, 250000, "pp-1", nil))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM refunds WHERE payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(0.0))
+				mock.ExpectExec(`UPDATE payments SET voided_at = CURRENT_TIMESTAMP`).
+					WithArgs("admin", "salah transfer", "pay-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE payment_proofs SET status = \?`).
+					WithArgs(domain.ProofStatusRejected, "admin", "salah transfer", "pay-1", "pp-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "payment voided by hand is not voided again",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				voidedAt := time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)
+				approvedProof(mock)
+				mock.ExpectQuery(`(?i)select .* from payments where id = \? for update`).
+					WithArgs("pay-1").
+					WillReturnRows(paymentRow(mock, "pay-1", domain.PaymentMethodTransfer, 250000, "pp-1", &voidedAt))
+				mock.ExpectExec(`UPDATE payment_proofs SET status = \?`).
+					WithArgs(domain.ProofStatusRejected, "admin", "salah transfer", "pay-1", "pp-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "refunded payment is not voided",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				approvedProof(mock)
+				mock.ExpectQuery(`(?i)select .* from payments where id = \? for update`).
+					WithArgs("pay-1").
+					WillReturnRows(paymentRow(mock, "pay-1", domain.PaymentMethodTransfer, 250000, "pp-1", nil))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM refunds WHERE payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(100000.0))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			err = svc.RejectPaymentProof(context.Background(), "pp-1", "admin", "salah transfer")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+var refundColumns = []string{"id", "order_id", "payment_id", "amount", "method", "reason", "status", "requested_by", "reviewed_by", "reviewed_at", "reject_reason", "credit_note_id", "created_at"}
+
+func refundRow(mock sqlmock.Sqlmock, id string, amount float64, method string, status string) *sqlmock.Rows {
+	createdAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	return mock.NewRows(refundColumns).AddRow(id, "o-1", "pay-1", amount, method, "pesanan batal", status, "admin", "", nil, "", "", createdAt)
+}
+
+func TestRequestRefund(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      float64
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name:   "refund within what is left of the payment",
+			amount: 150000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from payments where id = \? for update`).
+					WithArgs("pay-1").
+					WillReturnRows(paymentRow(mock, "pay-1", domain.PaymentMethodTransfer, 250000, "", nil))
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 250000))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM refunds WHERE payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(100000.0))
+				mock.ExpectExec(`INSERT INTO refunds`).
+					WithArgs(sqlmock.AnyArg(), "o-1", "pay-1", 150000.0, domain.RefundMethodTransfer, "pesanan batal", domain.RefundStatusPending, "admin").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "refund above what is left of the payment",
+			amount: 200000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from payments where id = \? for update`).
+					WithArgs("pay-1").
+					WillReturnRows(paymentRow(mock, "pay-1", domain.PaymentMethodTransfer, 250000, "", nil))
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 250000))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM refunds WHERE payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(100000.0))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			refund := &domain.Refund{Amount: tt.amount, Method: domain.RefundMethodTransfer, Reason: "pesanan batal", RequestedBy: "admin"}
+			result, err := svc.RequestRefund(context.Background(), "pay-1", refund)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.RefundStatusPending, result.Status)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApproveRefund(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "part of the payment refunded",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from refunds where id = \? for update`).
+					WithArgs("rf-1").
+					WillReturnRows(refundRow(mock, "rf-1", 100000, domain.RefundMethodTransfer, domain.RefundStatusPending))
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 250000))
+				mock.ExpectExec(`UPDATE refunds SET status = \?`).
+					WithArgs(domain.RefundStatusApproved, "owner", "", "", "rf-1", domain.RefundStatusPending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM payments WHERE order_id = \?`).
+					WithArgs("o-1").
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(250000.0))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM refunds WHERE order_id = \?`).
+					WithArgs("o-1", domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(100000.0))
+				mock.ExpectExec(`UPDATE orders SET status = \? WHERE id = \?`).
+					WithArgs(domain.OrderStatusPartiallyRefunded, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO order_history`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "everything refunded as a credit note",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from refunds where id = \? for update`).
+					WithArgs("rf-1").
+					WillReturnRows(refundRow(mock, "rf-1", 250000, domain.RefundMethodCreditNote, domain.RefundStatusPending))
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 250000))
+				mock.ExpectExec(`INSERT INTO credit_notes`).
+					WithArgs(sqlmock.AnyArg(), "user1", "o-1", 250000.0, 250000.0, "owner").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE refunds SET status = \?`).
+					WithArgs(domain.RefundStatusApproved, "owner", "", sqlmock.AnyArg(), "rf-1", domain.RefundStatusPending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM payments WHERE order_id = \?`).
+					WithArgs("o-1").
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(250000.0))
+				mock.ExpectQuery(`SELECT COALESCE\(SUM\(amount\), 0\) FROM refunds WHERE order_id = \?`).
+					WithArgs("o-1", domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(250000.0))
+				mock.ExpectExec(`UPDATE orders SET status = \? WHERE id = \?`).
+					WithArgs(domain.OrderStatusRefunded, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO order_history`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			result, err := svc.ApproveRefund(context.Background(), "rf-1", "owner")
+
+			assert.NoError(t, err)
+			assert.Equal(t, domain.RefundStatusApproved, result.Status)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApplyCreditNote(t *testing.T) {
+	creditNoteColumns := []string{"id", "username", "order_id", "amount", "balance", "issued_by", "created_at"}
+	createdAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		amount         float64
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedAmount float64
+	}{
+		{
+			name: "pays the outstanding balance from the credit note",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-2").
+					WillReturnRows(orderRow(mock, "o-2", domain.OrderStatusPending, 250000, 100000))
+				mock.ExpectQuery(`(?i)select .* from credit_notes where id = \? for update`).
+					WithArgs("cn-1").
+					WillReturnRows(mock.NewRows(creditNoteColumns).AddRow("cn-1", "user1", "o-1", 200000.0, 200000.0, "owner", createdAt))
+				mock.ExpectExec(`UPDATE credit_notes SET balance = balance \+ \?`).
+					WithArgs(-150000.0, "cn-1", -150000.0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO payments`).
+					WithArgs(sqlmock.AnyArg(), "o-2", domain.PaymentMethodCreditNote, 150000.0, "cn-1", "", "admin", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE orders SET reserved_until = NULL WHERE id = \?`).
+					WithArgs("o-2").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedAmount: 150000,
+		},
+		{
+			name:   "more than the credit note balance",
+			amount: 150000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-2").
+					WillReturnRows(orderRow(mock, "o-2", domain.OrderStatusPending, 250000, 0))
+				mock.ExpectQuery(`(?i)select .* from credit_notes where id = \? for update`).
+					WithArgs("cn-1").
+					WillReturnRows(mock.NewRows(creditNoteColumns).AddRow("cn-1", "user1", "o-1", 200000.0, 100000.0, "owner", createdAt))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			request := &web.ApplyCreditNoteRequest{CreditNoteId: "cn-1", Amount: tt.amount}
+			result, err := svc.ApplyCreditNote(context.Background(), "o-2", request, "admin")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAmount, result.Amount)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSummarisePayments(t *testing.T) {
+	voidedAt := time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)
+	order := &domain.Orders{Id: "o-1", Total: 250000}
+	payments := []*domain.Payment{
+		{Id: "pay-1", Amount: 200000},
+		{Id: "pay-2", Amount: 50000, VoidedAt: &voidedAt},
+	}
+	refunds := []*domain.Refund{
+		{Id: "rf-1", Amount: 50000, Status: domain.RefundStatusApproved},
+		{Id: "rf-2", Amount: 30000, Status: domain.RefundStatusPending},
+	}
+
+	summary := summarisePayments(order, payments, refunds)
+
+	assert.Equal(t, 50000.0, summary.Refunded)
+	assert.Equal(t, 150000.0, summary.Paid)
+	assert.Equal(t, 100000.0, summary.Outstanding)
+	assert.Equal(t, domain.PaymentStatePartial, summary.State)
+}
+
+func TestSummarisePaymentsBalance(t *testing.T) {
+	voidedAt := time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		payments    []*domain.Payment
+		refunds     []*domain.Refund
+		paid        float64
+		outstanding float64
+		state       string
+	}{
+		{name: "nothing paid", paid: 0, outstanding: 250000, state: domain.PaymentStateUnpaid},
+		{name: "only voided payments", payments: []*domain.Payment{{Amount: 250000, VoidedAt: &voidedAt}},
+			paid: 0, outstanding: 250000, state: domain.PaymentStateUnpaid},
+		{name: "down payment", payments: []*domain.Payment{{Amount: 100000}},
+			paid: 100000, outstanding: 150000, state: domain.PaymentStatePartial},
+		{name: "settled in two payments", payments: []*domain.Payment{{Amount: 100000}, {Amount: 150000}},
+			paid: 250000, outstanding: 0, state: domain.PaymentStatePaid},
+		{name: "overpaid", payments: []*domain.Payment{{Amount: 300000}},
+			paid: 300000, outstanding: 0, state: domain.PaymentStateOverpaid},
+		{
+			name:     "overpayment refunded",
+			payments: []*domain.Payment{{Amount: 300000}},
+			refunds:  []*domain.Refund{{Amount: 50000, Status: domain.RefundStatusApproved}},
+			paid:     250000, outstanding: 0, state: domain.PaymentStatePaid,
+		},
+		{
+			name:     "fully refunded",
+			payments: []*domain.Payment{{Amount: 250000}},
+			refunds:  []*domain.Refund{{Amount: 250000, Status: domain.RefundStatusApproved}, {Amount: 10000, Status: domain.RefundStatusRejected}},
+			paid:     0, outstanding: 250000, state: domain.PaymentStateUnpaid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := summarisePayments(&domain.Orders{Id: "o-1", Total: 250000}, tt.payments, tt.refunds)
+
+			assert.Equal(t, 250000.0, summary.Total)
+			assert.Equal(t, tt.paid, summary.Paid)
+			assert.Equal(t, tt.outstanding, summary.Outstanding)
+			assert.Equal(t, tt.state, summary.State)
+			assert.NotNil(t, summary.Payments)
+		})
+	}
+}
+
+func TestVoidPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		payment     *domain.Payment
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name:    "cash payment",
+			payment: &domain.Payment{Id: "pay-1", Method: domain.PaymentMethodCash, Amount: 100000},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select coalesce\(sum\(amount\), 0\) from refunds where payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(0.0))
+				mock.ExpectExec(`(?i)update payments set voided_at`).
+					WithArgs("admin", "salah input", "pay-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "credit note payment puts the amount back on the note",
+			payment: &domain.Payment{Id: "pay-1", Method: domain.PaymentMethodCreditNote, Amount: 40000, Reference: "cn-1"},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)from refunds where payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(0.0))
+				mock.ExpectExec(`(?i)update payments set voided_at`).
+					WithArgs("admin", "salah input", "pay-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`(?i)update credit_notes set balance = balance \+ \?`).
+					WithArgs(40000.0, "cn-1", 40000.0).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "refunded payment cannot be voided",
+			payment: &domain.Payment{Id: "pay-1", Method: domain.PaymentMethodTransfer, Amount: 100000},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)from refunds where payment_id = \?`).
+					WithArgs("pay-1", domain.RefundStatusPending, domain.RefundStatusApproved).
+					WillReturnRows(mock.NewRows([]string{"sum"}).AddRow(30000.0))
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.setupMock(mock)
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			svc := &ServiceImpl{repo: repository.NewRepositoryImpl(nil), db: db}
+			err = svc.voidPayment(context.Background(), tx, tt.payment, "admin", "salah input")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+// stubProvider hands back a fixed notification whatever the body.
+type stubProvider struct {
+	notification *gateway.Notification
+}
+
+func (p *stubProvider) Name() string {
+	return "midtrans"
+}
+
+func (p *stubProvider) CreatePaymentLink(ctx context.Context, request gateway.LinkRequest) (*gateway.Link, error) {
+	return &gateway.Link{}, nil
+}
+
+func (p *stubProvider) ParseNotification(body []byte) (*gateway.Notification, error) {
+	return p.notification, nil
+}
+
+var linkColumns = []string{"id", "order_id", "provider", "provider_order_id", "amount", "paid_amount", "token", "redirect_url", "status", "transaction_id", "payment_id", "created_at", "modified_at"}
+
+func linkRow(mock sqlmock.Sqlmock, status string, paidAmount float64, paymentId string) *sqlmock.Rows {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	return mock.NewRows(linkColumns).AddRow("pl-1", "o-1", "midtrans", "o-1-1760864400", 250000.0, paidAmount, "tok", "https://pay", status, "trx-1", paymentId, createdAt, createdAt)
+}
+
+func TestHandlePaymentNotification(t *testing.T) {
+	lockLink := func(mock sqlmock.Sqlmock, status string, paidAmount float64) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`(?i)select .* from payment_links where provider = \? and provider_order_id = \? for update`).
+			WithArgs("midtrans", "o-1-1760864400").
+			WillReturnRows(linkRow(mock, status, paidAmount, ""))
+	}
+
+	tests := []struct {
+		name      string
+		amount    float64
+		setupMock func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:   "paid in full confirms the order",
+			amount: 250000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockLink(mock, gateway.StatusPending, 0)
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusPending, 250000, 0))
+				mock.ExpectExec(`INSERT INTO payments`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE orders SET reserved_until = NULL WHERE id = \?`).
+					WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE orders SET status = \? WHERE id = \?`).
+					WithArgs(domain.OrderStatusConfirmed, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO order_history`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE payment_links SET status = \?`).
+					WithArgs(gateway.StatusPaid, 250000.0, "trx-1", sqlmock.AnyArg(), "pl-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "other amount is put up for review",
+			amount: 25000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockLink(mock, gateway.StatusPending, 0)
+				mock.ExpectExec(`UPDATE payment_links SET status = \?`).
+					WithArgs(gateway.StatusReview, 25000.0, "trx-1", "", "pl-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "resent notification for a link under review is ignored",
+			amount: 25000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockLink(mock, gateway.StatusReview, 25000)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "paid after the order was cancelled is recorded but not confirmed",
+			amount: 250000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockLink(mock, gateway.StatusPending, 0)
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusCancelled, 250000, 0))
+				mock.ExpectExec(`INSERT INTO payments`).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE payment_links SET status = \?`).
+					WithArgs(gateway.StatusReview, 250000.0, "trx-1", sqlmock.AnyArg(), "pl-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			provider := &stubProvider{notification: &gateway.Notification{
+				OrderId:       "o-1-1760864400",
+				TransactionId: "trx-1",
+				Status:        gateway.StatusPaid,
+				Amount:        tt.amount,
+			}}
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, provider)
+			err = svc.HandlePaymentNotification(context.Background(), nil)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestResolvePaymentLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedStatus string
+	}{
+		{
+			name:   "recording books the collected amount and confirms the order",
+			action: web.ResolveLinkRecord,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from payment_links where id = \? for update`).WithArgs("pl-1").
+					WillReturnRows(linkRow(mock, gateway.StatusReview, 25000, ""))
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusPending, 250000, 0))
+				mock.ExpectExec(`INSERT INTO payments`).
+					WithArgs(sqlmock.AnyArg(), "o-1", domain.PaymentMethodGateway, 25000.0, "trx-1", "", "midtrans", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE orders SET reserved_until = NULL`).WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE orders SET status = \?`).WithArgs(domain.OrderStatusConfirmed, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO order_history`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`UPDATE payment_links SET status = \?`).
+					WithArgs(gateway.StatusPaid, 25000.0, "trx-1", sqlmock.AnyArg(), "pl-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: gateway.StatusPaid,
+		},
+		{
+			name:   "recording a link paid after cancellation keeps the payment",
+			action: web.ResolveLinkRecord,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from payment_links where id = \? for update`).WithArgs("pl-1").
+					WillReturnRows(linkRow(mock, gateway.StatusReview, 250000, "pay-1"))
+				mock.ExpectExec(`UPDATE payment_links SET status = \?`).
+					WithArgs(gateway.StatusPaid, 250000.0, "trx-1", "pay-1", "pl-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: gateway.StatusPaid,
+		},
+		{
+			name:   "dismissing closes the link",
+			action: web.ResolveLinkDismiss,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from payment_links where id = \? for update`).WithArgs("pl-1").
+					WillReturnRows(linkRow(mock, gateway.StatusReview, 25000, ""))
+				mock.ExpectExec(`UPDATE payment_links SET status = \?`).
+					WithArgs(gateway.StatusFailed, 25000.0, "trx-1", "", "pl-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedStatus: gateway.StatusFailed,
+		},
+		{
+			name:   "dismissing a link with a booked payment is refused",
+			action: web.ResolveLinkDismiss,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from payment_links where id = \? for update`).WithArgs("pl-1").
+					WillReturnRows(linkRow(mock, gateway.StatusReview, 250000, "pay-1"))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+		{
+			name:   "link not under review",
+			action: web.ResolveLinkRecord,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from payment_links where id = \? for update`).WithArgs("pl-1").
+					WillReturnRows(linkRow(mock, gateway.StatusPaid, 250000, "pay-1"))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, &stubProvider{})
+			result, err := svc.ResolvePaymentLink(context.Background(), "pl-1", tt.action, "admin")
+
+			if tt.expectedErr {
+				var invalid *ValidationError
+				assert.ErrorAs(t, err, &invalid)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedStatus, result.Status)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFirstBookableDate(t *testing.T) {
+	settings := &domain.ProductionSettings{CutoffDays: 1, CutoffTime: "15:00"}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected string
+	}{
+		{
+			name:     "before the cut-off tomorrow is still open",
+			now:      time.Date(2026, 10, 19, 14, 0, 0, 0, helper.Location()),
+			expected: "2026-10-20",
+		},
+		{
+			name:     "after the cut-off tomorrow is closed",
+			now:      time.Date(2026, 10, 19, 16, 0, 0, 0, helper.Location()),
+			expected: "2026-10-21",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, err := firstBookableDate(settings, tt.now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, date.String())
+		})
+	}
+}
+
+func TestTrackingTimeline(t *testing.T) {
+	placedAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	confirmedAt := placedAt.Add(time.Hour)
+	deliveredAt := placedAt.Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		status   string
+		history  []*domain.OrderHistory
+		expected []*domain.TrackingEvent
+	}{
+		{
+			name:     "no history shows the current status",
+			status:   domain.OrderStatusPending,
+			expected: []*domain.TrackingEvent{{Status: domain.OrderStatusPending, At: &placedAt}},
+		},
+		{
+			name:   "status changes follow the placed status and edits are left out",
+			status: domain.OrderStatusDelivered,
+			history: []*domain.OrderHistory{
+				{Action: domain.OrderHistoryStatus, Changes: []domain.OrderChange{{Field: "status", From: domain.OrderStatusPending, To: domain.OrderStatusConfirmed}}, ChangedBy: "admin", CreatedAt: &confirmedAt},
+				{Action: domain.OrderHistoryEdit, Changes: []domain.OrderChange{{Field: "quantity", From: "10", To: "12"}}, ChangedBy: "admin", CreatedAt: &confirmedAt},
+				{Action: domain.OrderHistoryStatus, Changes: []domain.OrderChange{{Field: "status", From: domain.OrderStatusConfirmed, To: domain.OrderStatusDelivered}}, CreatedAt: &deliveredAt},
+			},
+			expected: []*domain.TrackingEvent{
+				{Status: domain.OrderStatusPending, At: &placedAt},
+				{Status: domain.OrderStatusConfirmed, At: &confirmedAt},
+				{Status: domain.OrderStatusDelivered, At: &deliveredAt},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &domain.Orders{Id: "o-1", Status: tt.status, CreatedAt: &placedAt}
+
+			assert.Equal(t, tt.expected, trackingTimeline(order, tt.history))
+		})
+	}
+}
+
+func TestAssignOrderCodes(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'KH-ABCDEF' for key 'orders.uq_orders_code'"}
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "a code taken by a concurrent order is replaced",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT id FROM orders WHERE code IS NULL").
+					WillReturnRows(mock.NewRows([]string{"id"}).AddRow("o-1"))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE orders SET code").WillReturnError(duplicate)
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE orders SET code").WithArgs(sqlmock.AnyArg(), "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "a failing order does not stop the others",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT id FROM orders WHERE code IS NULL").
+					WillReturnRows(mock.NewRows([]string{"id"}).AddRow("o-1").AddRow("o-2"))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE orders SET code").WithArgs(sqlmock.AnyArg(), "o-1").
+					WillReturnError(driver.ErrBadConn)
+				mock.ExpectRollback()
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT COUNT").WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("UPDATE orders SET code").WithArgs(sqlmock.AnyArg(), "o-2").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			err = svc.AssignOrderCodes(context.Background())
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNormaliseVoucherList(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      []string
+		expected    []string
+		expectedErr bool
+	}{
+		{name: "nil list", expected: []string{}},
+		{name: "entries are trimmed and deduplicated", values: []string{" P001", "P002", "", "P001 "}, expected: []string{"P001", "P002"}},
+		{name: "entry with a comma", values: []string{"Nasi, lauk"}, expectedErr: true},
+		{name: "list longer than the column", values: []string{strings.Repeat("x", 200), strings.Repeat("y", 60)}, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := normaliseVoucherList(tt.values, "produk")
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRepriceVoucher(t *testing.T) {
+	voucherColumns := []string{"id", "code", "description", "type", "value", "max_discount", "min_order", "usage_limit", "per_customer_limit", "used_count",
+		"product_ids", "categories", "valid_from", "valid_until", "active", "created_at", "modified_at"}
+
+	tests := []struct {
+		name             string
+		subtotal         float64
+		setupMock        func(mock sqlmock.Sqlmock)
+		expectedVoucher  string
+		expectedDiscount float64
+	}{
+		{
+			name:     "voucher still applies",
+			subtotal: 300000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE voucher_redemptions SET discount").WithArgs(30000.0, "o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			expectedVoucher:  "HEMAT10",
+			expectedDiscount: 30000,
+		},
+		{
+			name:     "subtotal below the minimum drops the voucher",
+			subtotal: 150000,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("UPDATE vouchers v JOIN voucher_redemptions").WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM voucher_redemptions").WithArgs("o-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT .* FROM vouchers WHERE code = \\? FOR UPDATE").WithArgs("HEMAT10").
+				WillReturnRows(mock.NewRows(voucherColumns).
+					AddRow("v1", "HEMAT10", "", "percentage", 10.0, 50000.0, 200000.0, 0, 0, 1, "", "", nil, nil, true, nil, nil))
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			svc := &ServiceImpl{repo: repository.NewRepositoryImpl(nil), db: db}
+			order := &domain.Orders{Id: "o-1", ProductId: "P001", Subtotal: tt.subtotal, VoucherCode: "HEMAT10"}
+			err = svc.repriceVoucher(context.Background(), tx, order, &domain.Domain{Id: "P001"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedVoucher, order.VoucherCode)
+			assert.Equal(t, tt.expectedDiscount, order.Discount)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSubmitFeedback(t *testing.T) {
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr error
+	}{
+		{
+			name: "delivered order without feedback",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM orders WHERE tracking_token = \\?").WithArgs("token-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusDelivered, 250000, 250000))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT .* FROM orders WHERE id = \\? FOR UPDATE").WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusDelivered, 250000, 250000))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM feedback WHERE order_id = \\?").WithArgs("o-1").
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec("INSERT INTO feedback").
+					WithArgs(sqlmock.AnyArg(), "o-1", "P001", "user1", 5, 4, "Enak", domain.FeedbackStatusPending).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "order not delivered yet",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM orders WHERE tracking_token = \\?").WithArgs("token-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusConfirmed, 250000, 250000))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT .* FROM orders WHERE id = \\? FOR UPDATE").WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusConfirmed, 250000, 250000))
+				mock.ExpectRollback()
+			},
+			expectedErr: errors.New("ulasan bisa dikirim setelah pesanan diterima"),
+		},
+		{
+			name: "feedback already sent for the order",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM orders WHERE tracking_token = \\?").WithArgs("token-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusDelivered, 250000, 250000))
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT .* FROM orders WHERE id = \\? FOR UPDATE").WithArgs("o-1").
+					WillReturnRows(orderRow(mock, "o-1", domain.OrderStatusDelivered, 250000, 250000))
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM feedback WHERE order_id = \\?").WithArgs("o-1").
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			expectedErr: ErrFeedbackSubmitted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			request := &web.FeedbackRequest{Rating: 5, ProductRating: 4, Comment: " Enak "}
+			result, err := svc.SubmitFeedback(context.Background(), "token-1", request)
+
+			if tt.expectedErr != nil {
+				assert.EqualError(t, err, tt.expectedErr.Error())
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Enak", result.Comment)
+				assert.Equal(t, domain.FeedbackStatusPending, result.Status)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestApplyDeliveryZone(t *testing.T) {
+	zoneColumns := []string{"id", "kecamatan", "desa", "fee", "min_order", "deliverable"}
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+		expectedFee float64
+	}{
+		{
+			name: "zone fee is applied",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM delivery_zones WHERE kecamatan = \\?").WithArgs("Cibinong", "Pakansari").
+					WillReturnRows(mock.NewRows(zoneColumns).AddRow("z1", "Cibinong", "", 10000, 150000, true))
+			},
+			expectedFee: 10000,
+		},
+		{
+			name: "below the zone minimum",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM delivery_zones WHERE kecamatan = \\?").WithArgs("Cibinong", "Pakansari").
+					WillReturnRows(mock.NewRows(zoneColumns).AddRow("z1", "Cibinong", "", 10000, 300000, true))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "address outside the zones",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM delivery_zones WHERE kecamatan = \\?").WithArgs("Cibinong", "Pakansari").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM delivery_zones").
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(3))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "no zones set up yet",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT .* FROM delivery_zones WHERE kecamatan = \\?").WithArgs("Cibinong", "Pakansari").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM delivery_zones").
+					WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.setupMock(mock)
+
+			tx, err := db.Begin()
+			assert.NoError(t, err)
+
+			svc := &ServiceImpl{repo: repository.NewRepositoryImpl(nil), db: db}
+			order := &domain.Orders{Kecamatan: "Cibinong", Desa: "Pakansari", Subtotal: 250000}
+			err = svc.applyDeliveryZone(context.Background(), tx, order)
+
+			if tt.expectedErr {
+				var invalid *ValidationError
+				assert.ErrorAs(t, err, &invalid)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedFee, order.DeliveryFee)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestNormaliseZone(t *testing.T) {
+	regions, err := region.NewIndex()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		zone        domain.DeliveryZone
+		expected    domain.DeliveryZone
+		expectedErr bool
+	}{
+		{
+			name:     "listed desa takes the dataset spelling",
+			zone:     domain.DeliveryZone{Kecamatan: "cibinong", Desa: "pakansari"},
+			expected: domain.DeliveryZone{Kecamatan: "Cibinong", Desa: "Pakansari"},
+		},
+		{
+			name:     "whole kecamatan",
+			zone:     domain.DeliveryZone{Kecamatan: "cibinong"},
+			expected: domain.DeliveryZone{Kecamatan: "Cibinong"},
+		},
+		{
+			name:     "desa of a kecamatan without listed desa is kept",
+			zone:     domain.DeliveryZone{Kecamatan: "tajurhalang", Desa: " Citayam "},
+			expected: domain.DeliveryZone{Kecamatan: "Tajurhalang", Desa: "Citayam"},
+		},
+		{
+			name:        "unknown desa of a listed kecamatan",
+			zone:        domain.DeliveryZone{Kecamatan: "Cibinong", Desa: "Atlantis"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &ServiceImpl{regions: regions}
+			zone := tt.zone
+			err := svc.normaliseZone(&zone)
+
+			if tt.expectedErr {
+				var invalid *ValidationError
+				assert.ErrorAs(t, err, &invalid)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, zone)
+			}
+		})
+	}
+}
+
+func TestConvertQuotation(t *testing.T) {
+	regions, err := region.NewIndex()
+	assert.NoError(t, err)
+
+	quotationColumns := []string{"id", "revision", "status", "event_name", "name", "phone", "username", "alamat", "kecamatan", "desa",
+		"delivery_date", "delivery_slot", "delivery_fee", "valid_until", "notes", "created_by", "created_at", "modified_at"}
+	itemColumns := []string{"id", "product_id", "product_name", "variant", "quantity", "unit_price", "order_id"}
+	deliveryDate := domain.Date{Time: helper.Now().AddDate(0, 0, 14)}
+
+	lockQuotation := func(mock sqlmock.Sqlmock, status string, items *sqlmock.Rows) {
+		mock.ExpectQuery("SELECT .* FROM quotations q JOIN quotation_revisions r").WithArgs(0, 0, "q-1").
+			WillReturnRows(mock.NewRows(quotationColumns).AddRow("q-1", 1, status, "Arisan", "Siti", "+6281234567890", "user1",
+				"Jl. Mawar 1", "Cibinong", "Pakansari", deliveryDate, "siang", 20000.0, nil, "", "admin", nil, nil))
+		mock.ExpectQuery("SELECT .* FROM quotation_items WHERE quotation_id = \\?").WithArgs("q-1", 1).
+			WillReturnRows(items)
+	}
+	checkSchedule := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT cutoff_days, cutoff_time FROM production_settings").
+			WillReturnRows(mock.NewRows([]string{"cutoff_days", "cutoff_time"}).AddRow(1, "15:00"))
+		mock.ExpectQuery("SELECT date, reason FROM blackout_dates WHERE date = \\?").
+			WillReturnError(sql.ErrNoRows)
+	}
+	zone := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery("SELECT .* FROM delivery_zones WHERE kecamatan = \\?").WithArgs("Cibinong", "Pakansari").
+			WillReturnRows(mock.NewRows([]string{"id", "kecamatan", "desa", "fee", "min_order", "deliverable"}).
+				AddRow("z1", "Cibinong", "", 15000, 500000, true))
+	}
+	createOrder := func(mock sqlmock.Sqlmock, productId string, subtotal float64, deliveryFee float64, itemId int) {
+		mock.ExpectQuery("SELECT id, name, stock, price, category FROM products WHERE id = \\?").WithArgs(productId).
+			WillReturnRows(mock.NewRows([]string{"id", "name", "stock", "price", "category"}).AddRow(productId, "Nasi Box", 500, 30000, "nasi"))
+		mock.ExpectQuery("SELECT category, max_portions FROM daily_capacities").
+			WillReturnRows(mock.NewRows([]string{"category", "max_portions"}))
+		mock.ExpectExec("INSERT INTO invoice_sequences").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery("SELECT LAST_INSERT_ID").WillReturnRows(mock.NewRows([]string{"id"}).AddRow(itemId))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM orders WHERE code = \\?").WillReturnRows(mock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec("INSERT INTO orders").
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), productId, "Nasi Box", "", "Siti", "+6281234567890",
+				"Jl. Mawar 1", "Cibinong", sqlmock.AnyArg(), "Pakansari", sqlmock.AnyArg(), "user1", sqlmock.AnyArg(), subtotal, 0.0, "",
+				deliveryFee, subtotal+deliveryFee, "", nil, sqlmock.AnyArg(), "siang", "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE products SET stock = stock - \\?").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE quotation_items SET order_id = \\?").WithArgs(sqlmock.AnyArg(), itemId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	tests := []struct {
+		name           string
+		setupMock      func(mock sqlmock.Sqlmock)
+		expectedErr    bool
+		expectedOrders int
+	}{
+		{
+			name: "zone minimum is met by the quotation total",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockQuotation(mock, domain.QuotationStatusAccepted, mock.NewRows(itemColumns).
+					AddRow(1, "P001", "Nasi Box", "", 10, 40000.0, "").
+					AddRow(2, "P002", "Nasi Box", "", 10, 30000.0, ""))
+				checkSchedule(mock)
+				zone(mock)
+				createOrder(mock, "P001", 400000, 20000, 1)
+				createOrder(mock, "P002", 300000, 0, 2)
+				mock.ExpectExec("UPDATE quotations SET status = \\?").WithArgs(domain.QuotationStatusConverted, "q-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expectedOrders: 2,
+		},
+		{
+			name: "quotation total below the zone minimum",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockQuotation(mock, domain.QuotationStatusAccepted, mock.NewRows(itemColumns).
+					AddRow(1, "P001", "Nasi Box", "", 10, 20000.0, "").
+					AddRow(2, "P002", "Nasi Box", "", 10, 10000.0, ""))
+				checkSchedule(mock)
+				zone(mock)
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+		{
+			name: "quotation not accepted yet",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				lockQuotation(mock, domain.QuotationStatusSent, mock.NewRows(itemColumns).
+					AddRow(1, "P001", "Nasi Box", "", 10, 40000.0, ""))
+				mock.ExpectRollback()
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectBegin()
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, regions, nil)
+			result, err := svc.ConvertQuotation(context.Background(), "q-1")
+
+			if tt.expectedErr {
+				var invalid *ValidationError
+				assert.ErrorAs(t, err, &invalid)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, tt.expectedOrders)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	expiredQuery := `(?i)select id from orders where status = \? and reserved_until < \? and id > \? order by id limit \?`
+	// alreadyHandled expects an order that was paid or changed since it was
+	// listed, which is locked and left alone.
+	alreadyHandled := func(mock sqlmock.Sqlmock, id string) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+			WithArgs(id).
+			WillReturnRows(orderRow(mock, id, domain.OrderStatusConfirmed, 150000, 150000))
+		mock.ExpectCommit()
+	}
+
+	tests := []struct {
+		name        string
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name: "failed order does not stop the rest",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expiredQuery).
+					WithArgs(domain.OrderStatusPending, sqlmock.AnyArg(), "", 100).
+					WillReturnRows(mock.NewRows([]string{"id"}).AddRow("o-1").AddRow("o-2"))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select .* from orders where id = \? for update`).
+					WithArgs("o-1").
+					WillReturnError(errors.New("lock wait timeout"))
+				mock.ExpectRollback()
+				alreadyHandled(mock, "o-2")
+			},
+			expectedErr: true,
+		},
+		{
+			name: "full batch moves on to the next page",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				page := mock.NewRows([]string{"id"})
+				var ids []string
+				for i := 0; i < 100; i++ {
+					id := fmt.Sprintf("o-%03d", i)
+					ids = append(ids, id)
+					page.AddRow(id)
+				}
+				mock.ExpectQuery(expiredQuery).
+					WithArgs(domain.OrderStatusPending, sqlmock.AnyArg(), "", 100).
+					WillReturnRows(page)
+				for _, id := range ids {
+					alreadyHandled(mock, id)
+				}
+				mock.ExpectQuery(expiredQuery).
+					WithArgs(domain.OrderStatusPending, sqlmock.AnyArg(), "o-099", 100).
+					WillReturnRows(mock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name: "listing fails",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(expiredQuery).
+					WithArgs(domain.OrderStatusPending, sqlmock.AnyArg(), "", 100).
+					WillReturnError(sql.ErrConnDone)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			err = svc.ReleaseExpiredReservations(context.Background())
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestBulkUpdateOrdersFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      *domain.OrderFilter
+		setupMock   func(mock sqlmock.Sqlmock)
+		expectedErr bool
+	}{
+		{
+			name:        "sort and limit alone select nothing",
+			filter:      &domain.OrderFilter{SortBy: domain.OrderSortTotal, Descending: true, Limit: 10},
+			setupMock:   func(mock sqlmock.Sqlmock) {},
+			expectedErr: true,
+		},
+		{
+			name:   "status filter selects orders",
+			filter: &domain.OrderFilter{Statuses: []string{domain.OrderStatusPending}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`(?i)select .* from orders where status in \(\?\)`).
+					WithArgs(domain.OrderStatusPending).
+					WillReturnRows(mock.NewRows(orderColumns))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			report, err := svc.BulkUpdateOrders(context.Background(), &web.BulkOrderRequest{Action: domain.BulkActionCancel}, tt.filter, "admin")
+
+			if tt.expectedErr {
+				var invalid *ValidationError
+				assert.True(t, errors.As(err, &invalid))
+				assert.Nil(t, report)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 0, report.Processed)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestExpandOrders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	paidAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`(?i)select .* from payments where order_id in \(\?, \?, \?\) order by paid_at, created_at`).
+		WithArgs("o-1", "o-2", "o-3").
+		WillReturnRows(mock.NewRows(paymentColumns).
+			AddRow("pay-1", "o-1", "cash", 50000.0, "", "", "admin", paidAt, nil, "", "", paidAt).
+			AddRow("pay-2", "o-3", "transfer", 20000.0, "", "", "admin", paidAt, nil, "", "", paidAt).
+			AddRow("pay-3", "o-1", "cash", 25000.0, "", "", "admin", paidAt, nil, "", "", paidAt))
+	mock.ExpectQuery(`(?i)select .* from order_history where order_id in \(\?, \?, \?\) order by id`).
+		WithArgs("o-1", "o-2", "o-3").
+		WillReturnRows(mock.NewRows([]string{"id", "order_id", "action", "changes", "changed_by", "created_at"}).
+			AddRow(1, "o-2", "status_changed", `[{"field":"status","from":"pending","to":"confirmed"}]`, "admin", paidAt).
+			AddRow(2, "o-1", "status_changed", `[{"field":"status","from":"pending","to":"confirmed"}]`, "admin", paidAt))
+
+	orders := []*domain.Orders{{Id: "o-1"}, {Id: "o-2"}, {Id: "o-3"}}
+	svc := &ServiceImpl{repo: repository.NewRepositoryImpl(nil), db: db}
+	err = svc.expandOrders(context.Background(), orders, domain.OrderExpand{Payments: true, History: true})
+
+	assert.NoError(t, err)
+	payments := map[string][]string{}
+	history := map[string]int{}
+	for _, order := range orders {
+		for _, payment := range order.Payments {
+			payments[order.Id] = append(payments[order.Id], payment.Id)
+		}
+		history[order.Id] = len(order.History)
+	}
+	assert.Equal(t, map[string][]string{"o-1": {"pay-1", "pay-3"}, "o-3": {"pay-2"}}, payments)
+	assert.Equal(t, map[string]int{"o-1": 1, "o-2": 1, "o-3": 0}, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckVoucherFits(t *testing.T) {
+	product := &domain.Domain{Id: "P001", Name: "Nasi Box", Category: "katering"}
+	tests := []struct {
+		name        string
+		voucher     *domain.Voucher
+		subtotal    float64
+		expectedErr string
+	}{
+		{name: "fits", voucher: &domain.Voucher{Code: "HEMAT", MinOrder: 100000, Categories: []string{"katering"}}, subtotal: 150000},
+		{name: "below minimum order", voucher: &domain.Voucher{Code: "HEMAT", MinOrder: 200000}, subtotal: 150000,
+			expectedErr: "voucher HEMAT berlaku untuk pesanan minimal " + helper.FormatRupiah(200000)},
+		{name: "other product", voucher: &domain.Voucher{Code: "HEMAT", ProductIds: []string{"P002"}}, subtotal: 150000,
+			expectedErr: "voucher HEMAT tidak berlaku untuk produk Nasi Box"},
+		{name: "other category", voucher: &domain.Voucher{Code: "HEMAT", Categories: []string{"kue"}}, subtotal: 150000,
+			expectedErr: "voucher HEMAT tidak berlaku untuk produk Nasi Box"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkVoucherFits(tt.voucher, &domain.Orders{Subtotal: tt.subtotal}, product)
+
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var invalid *ValidationError
+			if assert.True(t, errors.As(err, &invalid)) {
+				assert.Equal(t, tt.expectedErr, invalid.Message)
+			}
+		})
+	}
+}
+
+func TestAddDiscountRuleOverHundredPercent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+	rule := &domain.DiscountRule{Name: "Grosir", Type: domain.DiscountPercentage, Value: 120}
+
+	_, err = svc.AddDiscountRule(context.Background(), rule)
+	var invalid *ValidationError
+	assert.True(t, errors.As(err, &invalid))
+	err = svc.UpdateDiscountRule(context.Background(), rule, "rule-1")
+	assert.True(t, errors.As(err, &invalid))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeliveryManifest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	date, _ := domain.ParseDate("2026-10-20")
+	columns := []string{"id", "product_id", "product_name", "variant", "name", "phone", "alamat", "kecamatan", "desa", "quantity", "total", "amount_paid", "status", "delivery_slot", "customer_note"}
+	mock.ExpectQuery(`(?i)select .* from orders where delivery_date = \? and status in \(\?, \?, \?\) order by kecamatan, desa`).
+		WithArgs(date, domain.OrderStatusConfirmed, domain.OrderStatusCooking, domain.OrderStatusDelivering).
+		WillReturnRows(mock.NewRows(columns).
+			AddRow("o-1", "P001", "Nasi Box", "", "Siti", "+6281234567890", "Jl. Mawar 1", "Bojonggede", "Kedungwaringin", 10, 150000.0, 50000.0, domain.OrderStatusConfirmed, "pagi", "").
+			AddRow("o-2", "P001", "Nasi Box", "pedas", "Budi", "+6281234567891", "Jl. Melati 2", "Cibinong", "Pakansari", 5, 75000.0, 75000.0, domain.OrderStatusCooking, "siang", "pagar hijau").
+			AddRow("o-3", "P002", "Tumpeng", "", "Ani", "+6281234567892", "Jl. Kenanga 3", "Cibinong", "Pakansari", 1, 300000.0, 320000.0, domain.OrderStatusDelivering, "", ""))
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`(?i)select .* from order_notes where order_id in \(\?, \?, \?\)`).
+		WithArgs("o-1", "o-2", "o-3").
+		WillReturnRows(mock.NewRows([]string{"id", "order_id", "parent_id", "author", "body", "created_at"}).
+			AddRow("n-1", "o-1", "", "admin", "tagih sisa", createdAt))
+
+	svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+	manifest, err := svc.GetDeliveryManifest(context.Background(), date)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, manifest.TotalOrders)
+	assert.Equal(t, 100000.0, manifest.TotalToCollect)
+	if assert.Len(t, manifest.Areas, 2) {
+		assert.Equal(t, "Bojonggede", manifest.Areas[0].Kecamatan)
+		assert.Equal(t, 100000.0, manifest.Areas[0].TotalToCollect)
+		assert.Equal(t, []string{"admin: tagih sisa"}, manifest.Areas[0].Stops[0].Notes)
+
+		assert.Equal(t, "Pakansari", manifest.Areas[1].Desa)
+		assert.Equal(t, 0.0, manifest.Areas[1].TotalToCollect)
+		if assert.Len(t, manifest.Areas[1].Stops, 2) {
+			assert.Equal(t, "o-2", manifest.Areas[1].Stops[0].OrderId)
+			assert.Equal(t, "pagar hijau", manifest.Areas[1].Stops[0].CustomerNote)
+			// An overpaid order has nothing to collect rather than a negative amount.
+			assert.Equal(t, 0.0, manifest.Areas[1].Stops[1].AmountToCollect)
+		}
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateQuotationStatus(t *testing.T) {
+	quotationColumns := []string{"id", "revision", "status", "event_name", "name", "phone", "username", "alamat", "kecamatan", "desa",
+		"delivery_date", "delivery_slot", "delivery_fee", "valid_until", "notes", "created_by", "created_at", "modified_at"}
+	today := helper.Now()
+	yesterday := domain.Date{Time: today.AddDate(0, 0, -1)}
+	nextWeek := domain.Date{Time: today.AddDate(0, 0, 7)}
+
+	tests := []struct {
+		name        string
+		current     string
+		validUntil  *domain.Date
+		status      string
+		expectedErr bool
+	}{
+		{name: "send a draft", current: domain.QuotationStatusDraft, status: domain.QuotationStatusSent},
+		{name: "accept before it expires", current: domain.QuotationStatusSent, validUntil: &nextWeek, status: domain.QuotationStatusAccepted},
+		{name: "accept after it expired", current: domain.QuotationStatusSent, validUntil: &yesterday, status: domain.QuotationStatusAccepted, expectedErr: true},
+		{name: "reject after it expired", current: domain.QuotationStatusSent, validUntil: &yesterday, status: domain.QuotationStatusRejected},
+		{name: "accept a draft", current: domain.QuotationStatusDraft, status: domain.QuotationStatusAccepted, expectedErr: true},
+		{name: "reopen a rejected quotation", current: domain.QuotationStatusRejected, status: domain.QuotationStatusSent, expectedErr: true},
+		{name: "change a converted quotation", current: domain.QuotationStatusConverted, status: domain.QuotationStatusRejected, expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			assert.NoError(t, err)
+			defer db.Close()
+
+			var validUntil driver.Value
+			if tt.validUntil != nil {
+				validUntil = tt.validUntil.Time
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT .* FROM quotations q JOIN quotation_revisions r").WithArgs(0, 0, "q-1").
+				WillReturnRows(mock.NewRows(quotationColumns).AddRow("q-1", 1, tt.current, "Arisan", "Siti", "+6281234567890", "user1",
+					"Jl. Mawar 1", "Cibinong", "Pakansari", nil, "siang", 20000.0, validUntil, "", "admin", nil, nil))
+			mock.ExpectQuery("SELECT .* FROM quotation_items WHERE quotation_id = \\?").WithArgs("q-1", 1).
+				WillReturnRows(mock.NewRows([]string{"id", "product_id", "product_name", "variant", "quantity", "unit_price", "order_id"}))
+			if tt.expectedErr {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectExec("UPDATE quotations SET status = \\?").WithArgs(tt.status, "q-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			}
+
+			svc := NewServiceImpl(repository.NewRepositoryImpl(nil), db, nil, nil)
+			err = svc.UpdateQuotationStatus(context.Background(), "q-1", tt.status)
+
+			if tt.expectedErr {
+				var invalid *ValidationError
+				assert.True(t, errors.As(err, &invalid))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestThreadNotes(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	note := func(id string, parentId string) *domain.OrderNote {
+		return &domain.OrderNote{Id: id, OrderId: "o-1", ParentId: parentId, Author: "admin", Body: id, CreatedAt: &createdAt}
+	}
+	// shape lists each top-level note with the ids of its replies, nested the
+	// same way.
+	var shape func(notes []*domain.OrderNote) []string
+	shape = func(notes []*domain.OrderNote) []string {
+		result := []string{}
+		for _, n := range notes {
+			entry := n.Id
+			if len(n.Replies) > 0 {
+				entry += "(" + strings.Join(shape(n.Replies), ",") + ")"
+			}
+			result = append(result, entry)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		notes    []*domain.OrderNote
+		expected []string
+	}{
+		{name: "no notes", expected: []string{}},
+		{name: "replies nest under their parent in order",
+			notes:    []*domain.OrderNote{note("a", ""), note("b", ""), note("c", "a"), note("d", "c"), note("e", "a")},
+			expected: []string{"a(c(d),e)", "b"}},
+		{name: "reply sorted ahead of its parent",
+			notes:    []*domain.OrderNote{note("b", "a"), note("a", "")},
+			expected: []string{"a(b)"}},
+		{name: "reply to a note that was not loaded stays top-level",
+			notes:    []*domain.OrderNote{note("a", ""), note("b", "gone")},
+			expected: []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, shape(domain.ThreadNotes(tt.notes)))
+		})
+	}
+}
+
+func TestOrderNoteLines(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`(?i)select .* from order_notes where order_id in \(\?, \?\) order by created_at, id`).
+		WithArgs("o-1", "o-2").
+		WillReturnRows(mock.NewRows([]string{"id", "order_id", "parent_id", "author", "body", "created_at"}).
+			AddRow("n-1", "o-1", "", "dewi", "tanpa sambal", createdAt).
+			AddRow("n-2", "o-2", "", "rudi", "kirim pagi", createdAt).
+			AddRow("n-3", "o-1", "n-1", "rudi", "sudah dicatat", createdAt))
+
+	svc := &ServiceImpl{repo: repository.NewRepositoryImpl(nil), db: db}
+	lines, err := svc.orderNoteLines(context.Background(), []string{"o-1", "o-2"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"o-1": {"dewi: tanpa sambal", "rudi: sudah dicatat"},
+		"o-2": {"rudi: kirim pagi"},
+	}, lines)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// subscriptionLeadDays is how far ahead the scheduler turns subscriptions
+// into orders, counted from the first date still open for orders.
+const subscriptionLeadDays = 3
+
+func (svc *ServiceImpl) AddSubscription(ctx context.Context, subscription *domain.Subscription) (data *domain.Subscription, err error) {
+	if subscription.EndDate != nil && subscription.EndDate.Before(subscription.StartDate.Time) {
+		return nil, errors.New("tanggal selesai langganan harus setelah tanggal mulai")
+	}
+	slices.Sort(subscription.DaysOfWeek)
+	subscription.DaysOfWeek = slices.Compact(subscription.DaysOfWeek)
+	subscription.Phone, err = helper.NormalizePhone(subscription.Phone)
+	if err != nil {
+		return nil, err
+	}
+	kecamatan, desa, err := svc.regions.ResolveAddress(subscription.Kecamatan, subscription.Desa)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add subscription", "error", err.Error())
+		return nil, err
+	}
+	subscription.Kecamatan = kecamatan.Name
+	if desa != nil {
+		subscription.Desa = desa.Name
+	}
+
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add subscription", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	subscription.Id = uuid.New().String()
+	subscription.Status = domain.SubscriptionStatusActive
+	subscription.SkipDates = []domain.Date{}
+	err = svc.repo.AddSubscription(ctx, tx, subscription)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add subscription", "error", err.Error())
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (svc *ServiceImpl) GetSubscriptions(ctx context.Context, status string) ([]*domain.Subscription, error) {
+	subscriptions, err := svc.repo.GetSubscriptions(ctx, svc.db, status)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get subscriptions", "error", err.Error())
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (svc *ServiceImpl) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	subscription, err := svc.repo.GetSubscription(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get subscription", "error", err.Error())
+		return nil, err
+	}
+	subscription.SkipDates, err = svc.repo.GetSubscriptionSkips(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get subscription", "error", err.Error())
+		return nil, err
+	}
+	subscription.Runs, err = svc.repo.GetSubscriptionRuns(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get subscription", "error", err.Error())
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (svc *ServiceImpl) setSubscriptionStatus(ctx context.Context, id string, allowed []string, status string, pausedUntil *domain.Date) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update subscription", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	subscription, err := svc.repo.LockSubscription(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update subscription", "error", err.Error())
+		return err
+	}
+	if !slices.Contains(allowed, subscription.Status) {
+		err = fmt.Errorf("langganan berstatus %s tidak dapat diubah menjadi %s", subscription.Status, status)
+		return err
+	}
+	err = svc.repo.UpdateSubscriptionStatus(ctx, tx, id, status, pausedUntil)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update subscription", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// PauseSubscription stops new orders; with until set the subscription picks
+// up again by itself after that date.
+func (svc *ServiceImpl) PauseSubscription(ctx context.Context, id string, until *domain.Date) error {
+	return svc.setSubscriptionStatus(ctx, id, []string{domain.SubscriptionStatusActive, domain.SubscriptionStatusPaused}, domain.SubscriptionStatusPaused, until)
+}
+
+func (svc *ServiceImpl) ResumeSubscription(ctx context.Context, id string) error {
+	return svc.setSubscriptionStatus(ctx, id, []string{domain.SubscriptionStatusPaused}, domain.SubscriptionStatusActive, nil)
+}
+
+func (svc *ServiceImpl) CancelSubscription(ctx context.Context, id string) error {
+	return svc.setSubscriptionStatus(ctx, id, []string{domain.SubscriptionStatusActive, domain.SubscriptionStatusPaused}, domain.SubscriptionStatusCancelled, nil)
+}
+
+// SkipSubscriptionDate only affects dates that have not been turned into an
+// order yet; an order that already exists has to be cancelled on its own.
+func (svc *ServiceImpl) SkipSubscriptionDate(ctx context.Context, id string, date domain.Date) (err error) {
+	runs, err := svc.repo.GetSubscriptionRuns(ctx, svc.db, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("skip subscription date", "error", err.Error())
+		return err
+	}
+	for _, run := range runs {
+		if run.DeliveryDate.Equal(date.Time) && run.OrderId != "" {
+			return fmt.Errorf("pesanan langganan untuk tanggal %s sudah dibuat (%s)", date, run.OrderId)
+		}
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("skip subscription date", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	subscription, err := svc.repo.LockSubscription(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("skip subscription date", "error", err.Error())
+		return err
+	}
+	if subscription.Status == domain.SubscriptionStatusCancelled {
+		err = errors.New("langganan sudah dibatalkan")
+		return err
+	}
+	err = svc.repo.AddSubscriptionSkip(ctx, tx, id, date)
+	if err != nil {
+		logger.GetLogger("service-log").Log("skip subscription date", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) UnskipSubscriptionDate(ctx context.Context, id string, date domain.Date) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("unskip subscription date", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.DeleteSubscriptionSkip(ctx, tx, id, date)
+	if err != nil {
+		logger.GetLogger("service-log").Log("unskip subscription date", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// MaterialiseSubscriptions creates the orders for every subscription delivery
+// in the subscriptionLeadDays days after the first date that can still be
+// ordered, so dates already past the cut-off are not attempted. It is safe to
+// run repeatedly: each subscription and date produces at most one order.
+func (svc *ServiceImpl) MaterialiseSubscriptions(ctx context.Context) error {
+	settings, err := svc.repo.GetProductionSettings(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("materialise subscriptions", "error", err.Error())
+		return err
+	}
+	from, err := firstBookableDate(settings, helper.Now())
+	if err != nil {
+		logger.GetLogger("service-log").Log("materialise subscriptions", "error", err.Error())
+		return err
+	}
+	until := domain.Date{Time: from.AddDate(0, 0, subscriptionLeadDays)}
+	subscriptions, err := svc.repo.GetSchedulableSubscriptions(ctx, svc.db, from, until)
+	if err != nil {
+		logger.GetLogger("service-log").Log("materialise subscriptions", "error", err.Error())
+		return err
+	}
+	for _, subscription := range subscriptions {
+		subscription.SkipDates, err = svc.repo.GetSubscriptionSkips(ctx, svc.db, subscription.Id)
+		if err != nil {
+			logger.GetLogger("service-log").Log("materialise subscriptions", "error", err.Error())
+			return err
+		}
+		for date := from; !date.After(until.Time); date = (domain.Date{Time: date.AddDate(0, 0, 1)}) {
+			if !subscription.DeliversOn(date) {
+				continue
+			}
+			if err := svc.materialiseSubscription(ctx, subscription, date); err != nil {
+				logger.GetLogger("service-log").Log("materialise subscriptions", "error", err.Error())
+				// Keep going: one run that cannot be recorded should not hold
+				// up the other subscriptions.
+				if err := svc.repo.FailSubscriptionRun(ctx, svc.db, subscription.Id, date, truncate(err.Error(), 255)); err != nil {
+					logger.GetLogger("service-log").Log("materialise subscriptions", "error", err.Error())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) materialiseSubscription(ctx context.Context, subscription *domain.Subscription, date domain.Date) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	orderId, err := svc.repo.ClaimSubscriptionRun(ctx, tx, subscription.Id, date)
+	if err != nil || orderId != "" {
+		return err
+	}
+	err = svc.checkOrderSchedule(ctx, date)
+	if err != nil {
+		return err
+	}
+	order := &domain.Orders{
+		ProductId:    subscription.ProductId,
+		Variant:      subscription.Variant,
+		Name:         subscription.Name,
+		Phone:        subscription.Phone,
+		Alamat:       subscription.Alamat,
+		Kecamatan:    subscription.Kecamatan,
+		Desa:         subscription.Desa,
+		Username:     subscription.Username,
+		Quantity:     subscription.Quantity,
+		DeliveryDate: &date,
+		DeliverySlot: subscription.DeliverySlot,
+	}
+	err = svc.normaliseAddress(order)
+	if err != nil {
+		return err
+	}
+	err = svc.createOrder(ctx, tx, order, nil)
+	if err != nil {
+		return err
+	}
+	return svc.repo.CompleteSubscriptionRun(ctx, tx, subscription.Id, date, order.Id)
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length])
+}
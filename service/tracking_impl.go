@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"khaira-admin/domain"
+	"khaira-admin/logger"
+)
+
+// GetOrderTracking loads the public view of an order behind a tracking
+// token: its progress and balance, without who ordered it or where it goes.
+func (svc *ServiceImpl) GetOrderTracking(ctx context.Context, token string) (*domain.OrderTracking, error) {
+	order, err := svc.repo.GetOrderByTrackingToken(ctx, svc.db, token)
+	if err != nil {
+		return nil, err
+	}
+	history, err := svc.repo.GetOrderHistory(ctx, svc.db, order.Id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get order tracking", "error", err.Error())
+		return nil, err
+	}
+	tracking := &domain.OrderTracking{
+		Code:          order.Code,
+		InvoiceNumber: order.InvoiceNumber,
+		ProductName:   order.ProductName,
+		Variant:       order.Variant,
+		Quantity:      order.Quantity,
+		Status:        order.Status,
+		DeliveryDate:  order.DeliveryDate,
+		DeliverySlot:  order.DeliverySlot,
+		Total:         order.Total,
+		Paid:          order.AmountPaid,
+		Outstanding:   max(order.Total-order.AmountPaid, 0),
+		PaymentState:  order.PaymentState,
+		Timeline:      trackingTimeline(order, history),
+	}
+	return tracking, nil
+}
+
+// trackingTimeline lists the statuses the order went through, starting with
+// the one it was placed in. Edits and who made each change are left out.
+func trackingTimeline(order *domain.Orders, history []*domain.OrderHistory) []*domain.TrackingEvent {
+	var timeline []*domain.TrackingEvent
+	for _, entry := range history {
+		if entry.Action != domain.OrderHistoryStatus {
+			continue
+		}
+		for _, change := range entry.Changes {
+			if change.Field != "status" {
+				continue
+			}
+			if len(timeline) == 0 {
+				timeline = append(timeline, &domain.TrackingEvent{Status: change.From, At: order.CreatedAt})
+			}
+			timeline = append(timeline, &domain.TrackingEvent{Status: change.To, At: entry.CreatedAt})
+		}
+	}
+	if len(timeline) == 0 {
+		timeline = append(timeline, &domain.TrackingEvent{Status: order.Status, At: order.CreatedAt})
+	}
+	return timeline
+}
+
+func (svc *ServiceImpl) GetTrackingToken(ctx context.Context, orderId string) (string, error) {
+	token, err := svc.repo.GetTrackingToken(ctx, svc.db, orderId)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get tracking token", "error", err.Error())
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,17 @@
+package service
+
+import "fmt"
+
+// ValidationError is a request turned down by a business rule. Its message
+// is written for the user and can be shown as is, unlike database errors.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func validationError(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"khaira-admin/domain"
+	"khaira-admin/helper"
+	"khaira-admin/logger"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+func (svc *ServiceImpl) GetVouchers(ctx context.Context) ([]*domain.Voucher, error) {
+	vouchers, err := svc.repo.GetVouchers(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get vouchers", "error", err.Error())
+		return nil, err
+	}
+	return vouchers, nil
+}
+
+// voucherListLength is the size of the product_ids and categories columns,
+// which hold their lists comma separated.
+const voucherListLength = 255
+
+func normaliseVoucher(voucher *domain.Voucher) (err error) {
+	voucher.Code = strings.ToUpper(strings.TrimSpace(voucher.Code))
+	if voucher.Type == domain.DiscountPercentage && voucher.Value > 100 {
+		return validationError("persentase diskon tidak boleh lebih dari 100")
+	}
+	if voucher.ValidFrom != nil && voucher.ValidUntil != nil && voucher.ValidUntil.Before(*voucher.ValidFrom) {
+		return validationError("masa berlaku voucher tidak valid")
+	}
+	if voucher.ProductIds, err = normaliseVoucherList(voucher.ProductIds, "produk"); err != nil {
+		return err
+	}
+	voucher.Categories, err = normaliseVoucherList(voucher.Categories, "kategori")
+	return err
+}
+
+// normaliseVoucherList trims the entries of a voucher restriction list and
+// makes sure the list still fits its column once joined.
+func normaliseVoucherList(values []string, field string) ([]string, error) {
+	list := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || slices.Contains(list, value) {
+			continue
+		}
+		if strings.Contains(value, ",") {
+			return nil, validationError("%s voucher tidak boleh mengandung koma: %s", field, value)
+		}
+		list = append(list, value)
+	}
+	if length := len(strings.Join(list, ",")); length > voucherListLength {
+		return nil, validationError("daftar %s voucher terlalu panjang (%d dari maksimal %d karakter)", field, length, voucherListLength)
+	}
+	return list, nil
+}
+
+func (svc *ServiceImpl) AddVoucher(ctx context.Context, voucher *domain.Voucher) (data *domain.Voucher, err error) {
+	if err = normaliseVoucher(voucher); err != nil {
+		return nil, err
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add voucher", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	voucher.Id = uuid.New().String()
+	err = svc.repo.AddVoucher(ctx, tx, voucher)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add voucher", "error", err.Error())
+		return nil, err
+	}
+	return voucher, nil
+}
+
+func (svc *ServiceImpl) UpdateVoucher(ctx context.Context, voucher *domain.Voucher, id string) (err error) {
+	if err = normaliseVoucher(voucher); err != nil {
+		return err
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update voucher", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.UpdateVoucher(ctx, tx, voucher, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update voucher", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) DeactivateVoucher(ctx context.Context, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("deactivate voucher", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.DeactivateVoucher(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("deactivate voucher", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) GetDiscountRules(ctx context.Context) ([]*domain.DiscountRule, error) {
+	rules, err := svc.repo.GetDiscountRules(ctx, svc.db)
+	if err != nil {
+		logger.GetLogger("service-log").Log("get discount rules", "error", err.Error())
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (svc *ServiceImpl) AddDiscountRule(ctx context.Context, rule *domain.DiscountRule) (data *domain.DiscountRule, err error) {
+	if rule.Type == domain.DiscountPercentage && rule.Value > 100 {
+		return nil, validationError("persentase diskon tidak boleh lebih dari 100")
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("add discount rule", "error", err.Error())
+		return nil, err
+	}
+	defer helper.WithTransaction(tx, &err)
+	rule.Id = uuid.New().String()
+	err = svc.repo.AddDiscountRule(ctx, tx, rule)
+	if err != nil {
+		logger.GetLogger("service-log").Log("add discount rule", "error", err.Error())
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (svc *ServiceImpl) UpdateDiscountRule(ctx context.Context, rule *domain.DiscountRule, id string) (err error) {
+	if rule.Type == domain.DiscountPercentage && rule.Value > 100 {
+		return validationError("persentase diskon tidak boleh lebih dari 100")
+	}
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("update discount rule", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.UpdateDiscountRule(ctx, tx, rule, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("update discount rule", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+func (svc *ServiceImpl) DeleteDiscountRule(ctx context.Context, id string) (err error) {
+	tx, err := svc.db.Begin()
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete discount rule", "error", err.Error())
+		return err
+	}
+	defer helper.WithTransaction(tx, &err)
+	err = svc.repo.DeleteDiscountRule(ctx, tx, id)
+	if err != nil {
+		logger.GetLogger("service-log").Log("delete discount rule", "error", err.Error())
+		return err
+	}
+	return nil
+}
+
+// applyDiscounts takes the best automatic rule the order qualifies for and
+// then the voucher, if any, on what is left. The voucher row stays locked
+// until the order transaction ends so usage limits hold under concurrency;
+// the caller redeems the returned voucher once the order has an id.
+func (svc *ServiceImpl) applyDiscounts(ctx context.Context, tx *sql.Tx, order *domain.Orders, product *domain.Domain) (*domain.Voucher, float64, error) {
+	now := helper.Now()
+	discount, err := svc.ruleDiscount(ctx, tx, order, product)
+	if err != nil {
+		return nil, 0, err
+	}
+	order.Discount = discount
+
+	order.VoucherCode = strings.ToUpper(strings.TrimSpace(order.VoucherCode))
+	if order.VoucherCode == "" {
+		return nil, 0, nil
+	}
+	voucher, err := svc.repo.LockVoucherByCode(ctx, tx, order.VoucherCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, validationError("kode voucher %s tidak ditemukan", order.VoucherCode)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if !voucher.Active || !domain.ValidAt(voucher.ValidFrom, voucher.ValidUntil, now) {
+		return nil, 0, validationError("voucher %s tidak berlaku", voucher.Code)
+	}
+	if err := checkVoucherFits(voucher, order, product); err != nil {
+		return nil, 0, err
+	}
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return nil, 0, validationError("kuota voucher %s sudah habis", voucher.Code)
+	}
+	if voucher.PerCustomerLimit > 0 {
+		used, err := svc.repo.CountVoucherRedemptions(ctx, tx, voucher.Id, order.Username)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= voucher.PerCustomerLimit {
+			return nil, 0, validationError("voucher %s sudah digunakan %d kali oleh pelanggan ini", voucher.Code, used)
+		}
+	}
+	voucherDiscount := domain.DiscountAmount(voucher.Type, voucher.Value, voucher.MaxDiscount, order.Subtotal-discount)
+	order.Discount += voucherDiscount
+	return voucher, voucherDiscount, nil
+}
+
+// ruleDiscount returns the best automatic discount for the order; rules do not
+// stack.
+func (svc *ServiceImpl) ruleDiscount(ctx context.Context, tx *sql.Tx, order *domain.Orders, product *domain.Domain) (float64, error) {
+	rules, err := svc.repo.GetApplicableDiscountRules(ctx, tx, product.Id, product.Category, order.Quantity, helper.Now())
+	if err != nil {
+		return 0, err
+	}
+	discount := 0.0
+	for _, rule := range rules {
+		discount = max(discount, domain.DiscountAmount(rule.Type, rule.Value, 0, order.Subtotal))
+	}
+	return discount, nil
+}
+
+func checkVoucherFits(voucher *domain.Voucher, order *domain.Orders, product *domain.Domain) error {
+	if order.Subtotal < voucher.MinOrder {
+		return validationError("voucher %s berlaku untuk pesanan minimal %s", voucher.Code, helper.FormatRupiah(voucher.MinOrder))
+	}
+	if len(voucher.ProductIds) > 0 && !slices.Contains(voucher.ProductIds, product.Id) ||
+		len(voucher.Categories) > 0 && !slices.Contains(voucher.Categories, product.Category) {
+		return validationError("voucher %s tidak berlaku untuk produk %s", voucher.Code, product.Name)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+package web
+
+type BulkOrderRequest struct {
+	Action  string   `json:"action" validate:"required,oneof=status courier cancel"`
+	Status  string   `json:"status" validate:"required_if=Action status"`
+	Courier string   `json:"courier" validate:"max=100"`
+	Ids     []string `json:"ids" validate:"max=500,dive,required"`
+}
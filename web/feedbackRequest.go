@@ -0,0 +1,11 @@
+package web
+
+type FeedbackRequest struct {
+	Rating        int    `json:"rating" validate:"required,min=1,max=5"`
+	ProductRating int    `json:"product_rating" validate:"required,min=1,max=5"`
+	Comment       string `json:"comment" validate:"max=1000"`
+}
+
+type ModerateFeedbackRequest struct {
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+}
@@ -0,0 +1,6 @@
+package web
+
+type OrderNoteRequest struct {
+	ParentId string `json:"parent_id"`
+	Body     string `json:"body" validate:"required,max=2000"`
+}
@@ -0,0 +1,12 @@
+package web
+
+type EditOrderRequest struct {
+	ProductId    *string `json:"product_id" validate:"omitempty,min=1"`
+	Variant      *string `json:"variant" validate:"omitempty,max=50"`
+	Quantity     *int    `json:"quantity" validate:"omitempty,gt=0"`
+	Alamat       *string `json:"alamat" validate:"omitempty,min=1,max=255"`
+	Kecamatan    *string `json:"kecamatan" validate:"omitempty,min=1"`
+	Desa         *string `json:"desa" validate:"omitempty,min=1"`
+	DeliveryDate *string `json:"delivery_date" validate:"omitempty,datetime=2006-01-02"`
+	DeliverySlot *string `json:"delivery_slot" validate:"omitempty,max=20"`
+}
@@ -0,0 +1,21 @@
+package web
+
+type VoidPaymentRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+const (
+	ResolveLinkRecord  = "record"
+	ResolveLinkDismiss = "dismiss"
+)
+
+// ResolvePaymentLinkRequest settles a payment link held for review: record
+// books the amount the provider collected, dismiss closes the link when the
+// money was sent back outside the app.
+type ResolvePaymentLinkRequest struct {
+	Action string `json:"action" validate:"required,oneof=record dismiss"`
+}
+
+type RejectProofRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
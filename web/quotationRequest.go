@@ -0,0 +1,5 @@
+package web
+
+type QuotationStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=sent accepted rejected"`
+}
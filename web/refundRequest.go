@@ -0,0 +1,12 @@
+package web
+
+type RejectRefundRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// ApplyCreditNoteRequest spends a credit note on an order. Without an amount
+// the order's outstanding balance is paid as far as the credit note allows.
+type ApplyCreditNoteRequest struct {
+	CreditNoteId string  `json:"credit_note_id" validate:"required"`
+	Amount       float64 `json:"amount" validate:"gte=0"`
+}
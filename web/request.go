@@ -10,6 +10,7 @@ type Request struct {
 	Description   string     `json:"description"`
 	Stock         int        `json:"stock" validate:"required,number"`
 	Price         int        `json:"price" validate:"required,number"`
+	Category      string     `json:"category" validate:"max=50"`
 	ImageMetadata string     `json:"image_metadata" validate:"max=255"`
 	CreatedAt     *time.Time `json:"created_at"`
 	ModifiedAt    *time.Time `json:"modified_at"`
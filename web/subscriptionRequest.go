@@ -0,0 +1,9 @@
+package web
+
+type PauseSubscriptionRequest struct {
+	Until string `json:"until" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type SkipDateRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+}
@@ -7,17 +7,18 @@
 package main
 
 import (
-	"github.com/gofiber/fiber/v2"
 	"github.com/google/wire"
 	"khaira-admin/controller"
+	"khaira-admin/gateway"
 	"khaira-admin/helper"
+	"khaira-admin/region"
 	"khaira-admin/repository"
 	"khaira-admin/service"
 )
 
 // Injectors from injector.go:
 
-func InitServer() (*fiber.App, func(), error) {
+func InitServer() (*Server, func(), error) {
 	client, err := helper.NewElasticClient()
 	if err != nil {
 		return nil, nil, err
@@ -27,14 +28,22 @@ func InitServer() (*fiber.App, func(), error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	serviceService := service.NewServiceImpl(repositoryRepository, db)
+	index, err := region.NewIndex()
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	provider := gateway.NewProvider()
+	serviceService := service.NewServiceImpl(repositoryRepository, db, index, provider)
 	controllerController := controller.NewControllerImpl(serviceService)
 	app := NewServer(controllerController)
-	return app, func() {
+	schedulerScheduler := NewJobs(serviceService)
+	server := NewApplication(app, schedulerScheduler)
+	return server, func() {
 		cleanup()
 	}, nil
 }
 
 // injector.go:
 
-var ServerSet = wire.NewSet(helper.NewElasticClient, repository.NewRepositoryImpl, service.NewServiceImpl, controller.NewControllerImpl, helper.NewDb, NewServer)
+var ServerSet = wire.NewSet(helper.NewElasticClient, repository.NewRepositoryImpl, service.NewServiceImpl, controller.NewControllerImpl, helper.NewDb, region.NewIndex, gateway.NewProvider, NewServer, NewJobs, NewApplication)